@@ -13,6 +13,7 @@ type contextKey string
 // in the request context.
 const userContextKey = contextKey("user")
 const userInfoContextKey = contextKey("userInfo")
+const permissionsContextKey = contextKey("permissions")
 
 // The contextSetUser() method returns a new copy of the request with the provided
 // User struct added to the context. Note that we use our userContextKey constant as the
@@ -46,3 +47,38 @@ func (app *application) contextGetUserInfo(r *http.Request) *data.UserInfo {
 	}
 	return userInfo
 }
+
+// contextSetPermissions stores the permission codes carried by a signed access token,
+// so that requirePermission doesn't need to look them up.
+func (app *application) contextSetPermissions(r *http.Request, permissions data.Permissions) *http.Request {
+	ctx := context.WithValue(r.Context(), permissionsContextKey, permissions)
+	return r.WithContext(ctx)
+}
+
+func (app *application) contextGetPermissions(r *http.Request) (data.Permissions, bool) {
+	permissions, ok := r.Context().Value(permissionsContextKey).(data.Permissions)
+	return permissions, ok
+}
+
+// The gRPC interceptors get a context rather than a request, so they store the user
+// info and permissions under the same keys through these helpers instead.
+func (app *application) grpcContextSetUserInfo(ctx context.Context, userInfo *data.UserInfo) context.Context {
+	return context.WithValue(ctx, userInfoContextKey, userInfo)
+}
+
+func (app *application) grpcContextGetUserInfo(ctx context.Context) *data.UserInfo {
+	userInfo, ok := ctx.Value(userInfoContextKey).(*data.UserInfo)
+	if !ok {
+		panic("missing userInfo value in rpc context")
+	}
+	return userInfo
+}
+
+func (app *application) grpcContextSetPermissions(ctx context.Context, permissions data.Permissions) context.Context {
+	return context.WithValue(ctx, permissionsContextKey, permissions)
+}
+
+func (app *application) grpcContextGetPermissions(ctx context.Context) (data.Permissions, bool) {
+	permissions, ok := ctx.Value(permissionsContextKey).(data.Permissions)
+	return permissions, ok
+}
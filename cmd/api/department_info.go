@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
 	"net/http"
+	"strconv"
 )
 
 func (app *application) createDepInfoHandler(w http.ResponseWriter, r *http.Request) {
@@ -17,7 +19,8 @@ func (app *application) createDepInfoHandler(w http.ResponseWriter, r *http.Requ
 
 	err := app.readJSON(w, r, &input)
 	if err != nil {
-		app.errorResponse(w, r, http.StatusBadRequest, err.Error())
+		app.badRequestResponse(w, r, err)
+		return
 	}
 
 	departmentInfo := &data.DepartmentInfo{
@@ -27,14 +30,27 @@ func (app *application) createDepInfoHandler(w http.ResponseWriter, r *http.Requ
 		ModuleId:           input.ModuleId,
 	}
 
+	v := validator.New()
+	if data.ValidateDepartmentInfo(v, departmentInfo); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
 	err = app.models.DepartmentInfos.Insert(departmentInfo)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		switch {
+		case errors.Is(err, data.ErrModuleNotFound):
+			v.AddError("moduleId", "must reference an existing module")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
 	headers := make(http.Header)
-	headers.Set("Location", fmt.Sprintf("/v1/department-info/%d", departmentInfo.ID))
+	headers.Set("Location", fmt.Sprintf("/v1/department-infos/%d", departmentInfo.ID))
+	headers.Set("ETag", versionETag(strconv.Itoa(departmentInfo.Version)))
 
 	err = app.writeJSON(w, http.StatusCreated, Envelope{"department_info": departmentInfo}, headers)
 	if err != nil {
@@ -46,6 +62,7 @@ func (app *application) getDepartmentInfoHandler(w http.ResponseWriter, r *http.
 	id, err := app.readIDParam(r)
 	if err != nil {
 		app.notFoundResponse(w, r)
+		return
 	}
 
 	departmentInfo, err := app.models.DepartmentInfos.Get(id)
@@ -59,7 +76,204 @@ func (app *application) getDepartmentInfoHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, Envelope{"department_info": departmentInfo}, nil)
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(strconv.Itoa(departmentInfo.Version)))
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"department_info": departmentInfo}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) listDepartmentInfosHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ModuleID int
+		Director string
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	input.ModuleID = app.readInt(qs, "module_id", 0, v)
+	input.Director = app.readString(qs, "director", "")
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Filters.Sort = app.readString(qs, "sort", "id")
+	input.Filters.SortSafelist = data.DepartmentInfoSortSafelist
+
+	if data.ValidateDepartmentInfoFilters(v, input.ModuleID, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	departmentInfos, metadata, err := app.models.DepartmentInfos.GetAll(input.ModuleID, input.Director, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"department_infos": departmentInfos, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateDepartmentInfoHandler replaces every field of the department info.
+func (app *application) updateDepartmentInfoHandler(w http.ResponseWriter, r *http.Request) {
+	departmentInfo, ok := app.readDepartmentInfoForUpdate(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		DepartmentName     string `json:"departmentName"`
+		StaffQuantity      int    `json:"staffQuantity"`
+		DepartmentDirector string `json:"departmentDirector"`
+		ModuleId           int    `json:"moduleId"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	departmentInfo.DepartmentName = input.DepartmentName
+	departmentInfo.StaffQuantity = input.StaffQuantity
+	departmentInfo.DepartmentDirector = input.DepartmentDirector
+	departmentInfo.ModuleId = input.ModuleId
+
+	v := validator.New()
+	if data.ValidateDepartmentInfo(v, departmentInfo); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	app.saveDepartmentInfo(w, r, v, departmentInfo)
+}
+
+// patchDepartmentInfoHandler applies a partial update: only the fields present in the
+// request body are validated and changed.
+func (app *application) patchDepartmentInfoHandler(w http.ResponseWriter, r *http.Request) {
+	departmentInfo, ok := app.readDepartmentInfoForUpdate(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		DepartmentName     *string `json:"departmentName"`
+		StaffQuantity      *int    `json:"staffQuantity"`
+		DepartmentDirector *string `json:"departmentDirector"`
+		ModuleId           *int    `json:"moduleId"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if input.DepartmentName != nil {
+		data.ValidateDepartmentName(v, *input.DepartmentName)
+		departmentInfo.DepartmentName = *input.DepartmentName
+	}
+	if input.StaffQuantity != nil {
+		data.ValidateStaffQuantity(v, *input.StaffQuantity)
+		departmentInfo.StaffQuantity = *input.StaffQuantity
+	}
+	if input.DepartmentDirector != nil {
+		data.ValidateDepartmentDirector(v, *input.DepartmentDirector)
+		departmentInfo.DepartmentDirector = *input.DepartmentDirector
+	}
+	if input.ModuleId != nil {
+		data.ValidateDepartmentModuleId(v, *input.ModuleId)
+		departmentInfo.ModuleId = *input.ModuleId
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	app.saveDepartmentInfo(w, r, v, departmentInfo)
+}
+
+func (app *application) deleteDepartmentInfoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.DepartmentInfos.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "department info successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// readDepartmentInfoForUpdate fetches the department info named in the URL and checks
+// the If-Match header against its version. If anything goes wrong the error response
+// has already been sent and ok is false.
+func (app *application) readDepartmentInfoForUpdate(w http.ResponseWriter, r *http.Request) (*data.DepartmentInfo, bool) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return nil, false
+	}
+
+	departmentInfo, err := app.models.DepartmentInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return nil, false
+	}
+
+	if !ifMatchSatisfied(r, versionETag(strconv.Itoa(departmentInfo.Version))) {
+		app.preconditionFailedResponse(w, r)
+		return nil, false
+	}
+
+	return departmentInfo, true
+}
+
+// saveDepartmentInfo writes an already validated department info and sends it back
+// to the client.
+func (app *application) saveDepartmentInfo(w http.ResponseWriter, r *http.Request, v *validator.Validator, departmentInfo *data.DepartmentInfo) {
+	err := app.models.DepartmentInfos.Update(departmentInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrModuleNotFound):
+			v.AddError("moduleId", "must reference an existing module")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(strconv.Itoa(departmentInfo.Version)))
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"department_info": departmentInfo}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
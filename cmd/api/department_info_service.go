@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	pb "github.com/bxiit/greenlight/rpc"
+)
+
+// DepartmentInfoService serves rpc.DepartmentInfoService over a
+// DepartmentInfoRepository, mirroring the /v1/department-infos handlers.
+type DepartmentInfoService struct {
+	pb.UnimplementedDepartmentInfoServiceServer
+	Repo data.DepartmentInfoRepository
+	app  *application
+}
+
+func (s *DepartmentInfoService) CreateDepartmentInfo(ctx context.Context, req *pb.CreateDepartmentInfoRequest) (*pb.DepartmentInfo, error) {
+	departmentInfo := &data.DepartmentInfo{
+		DepartmentName:     req.DepartmentName,
+		StaffQuantity:      int(req.StaffQuantity),
+		DepartmentDirector: req.DepartmentDirector,
+		ModuleId:           int(req.ModuleId),
+	}
+
+	v := validator.New()
+	if data.ValidateDepartmentInfo(v, departmentInfo); !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	err := s.Repo.Insert(departmentInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrModuleNotFound):
+			v.AddError("moduleId", "must reference an existing module")
+			return nil, failedValidationStatus(v.Errors)
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return departmentInfoToProto(departmentInfo), nil
+}
+
+func (s *DepartmentInfoService) GetDepartmentInfo(ctx context.Context, req *pb.GetDepartmentInfoRequest) (*pb.DepartmentInfo, error) {
+	departmentInfo, err := s.Repo.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return departmentInfoToProto(departmentInfo), nil
+}
+
+func (s *DepartmentInfoService) ListDepartmentInfos(ctx context.Context, req *pb.ListDepartmentInfosRequest) (*pb.ListDepartmentInfosResponse, error) {
+	filters := data.Filters{
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		Sort:         req.Sort,
+		SortSafelist: data.DepartmentInfoSortSafelist,
+	}
+	// Zero values mean the field wasn't set; use the same defaults as
+	// listDepartmentInfosHandler.
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = "id"
+	}
+
+	v := validator.New()
+	if data.ValidateDepartmentInfoFilters(v, int(req.ModuleId), filters); !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	departmentInfos, metadata, err := s.Repo.GetAll(int(req.ModuleId), req.Director, filters)
+	if err != nil {
+		return nil, s.app.serverErrorStatus(ctx, err)
+	}
+
+	resp := &pb.ListDepartmentInfosResponse{
+		DepartmentInfos: make([]*pb.DepartmentInfo, 0, len(departmentInfos)),
+		Metadata:        metadataToProto(metadata),
+	}
+	for _, departmentInfo := range departmentInfos {
+		resp.DepartmentInfos = append(resp.DepartmentInfos, departmentInfoToProto(departmentInfo))
+	}
+	return resp, nil
+}
+
+// UpdateDepartmentInfo works like patchDepartmentInfoHandler, with the version field
+// in place of the If-Match header.
+func (s *DepartmentInfoService) UpdateDepartmentInfo(ctx context.Context, req *pb.UpdateDepartmentInfoRequest) (*pb.DepartmentInfo, error) {
+	departmentInfo, err := s.Repo.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	if req.Version != 0 && int(req.Version) != departmentInfo.Version {
+		return nil, preconditionFailedStatus()
+	}
+
+	v := validator.New()
+	if req.DepartmentName != nil {
+		data.ValidateDepartmentName(v, *req.DepartmentName)
+		departmentInfo.DepartmentName = *req.DepartmentName
+	}
+	if req.StaffQuantity != nil {
+		data.ValidateStaffQuantity(v, int(*req.StaffQuantity))
+		departmentInfo.StaffQuantity = int(*req.StaffQuantity)
+	}
+	if req.DepartmentDirector != nil {
+		data.ValidateDepartmentDirector(v, *req.DepartmentDirector)
+		departmentInfo.DepartmentDirector = *req.DepartmentDirector
+	}
+	if req.ModuleId != nil {
+		data.ValidateDepartmentModuleId(v, int(*req.ModuleId))
+		departmentInfo.ModuleId = int(*req.ModuleId)
+	}
+	if !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	err = s.Repo.Update(departmentInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrModuleNotFound):
+			v.AddError("moduleId", "must reference an existing module")
+			return nil, failedValidationStatus(v.Errors)
+		case errors.Is(err, data.ErrEditConflict):
+			return nil, editConflictStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return departmentInfoToProto(departmentInfo), nil
+}
+
+func (s *DepartmentInfoService) DeleteDepartmentInfo(ctx context.Context, req *pb.DeleteDepartmentInfoRequest) (*pb.DeleteDepartmentInfoResponse, error) {
+	err := s.Repo.Delete(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return &pb.DeleteDepartmentInfoResponse{}, nil
+}
+
+func departmentInfoToProto(departmentInfo *data.DepartmentInfo) *pb.DepartmentInfo {
+	return &pb.DepartmentInfo{
+		Id:                 int64(departmentInfo.ID),
+		DepartmentName:     departmentInfo.DepartmentName,
+		StaffQuantity:      int32(departmentInfo.StaffQuantity),
+		DepartmentDirector: departmentInfo.DepartmentDirector,
+		ModuleId:           int64(departmentInfo.ModuleId),
+		Version:            int32(departmentInfo.Version),
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestDepartmentInfoLifecycle(t *testing.T) {
+	ts := newTestServer(t)
+	token := ts.loginAsAdmin(t)
+
+	module := &data.ModuleInfo{ModuleName: "Algebra", ModuleDuration: 10, ExamType: "Final"}
+	err := ts.app.models.ModuleInfos.Create(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := ts.do(t, http.MethodPost, "/v1/department-infos", token, map[string]any{
+		"departmentName":     "Mathematics",
+		"staffQuantity":      12,
+		"departmentDirector": "Jane Doe",
+		"moduleId":           module.ID + 1,
+	})
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("create with a missing module: got status %d; want %d: %v", status, http.StatusUnprocessableEntity, body)
+	}
+
+	status, body = ts.do(t, http.MethodPost, "/v1/department-infos", token, map[string]any{
+		"departmentName":     "Mathematics",
+		"staffQuantity":      12,
+		"departmentDirector": "Jane Doe",
+		"moduleId":           module.ID,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("create: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	id := int(body["department_info"].(map[string]any)["id"].(float64))
+
+	status, body = ts.do(t, http.MethodGet, "/v1/department-infos?director=jane", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("list: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	if got := len(body["department_infos"].([]any)); got != 1 {
+		t.Errorf("list: got %d department infos; want 1", got)
+	}
+
+	status, body = ts.do(t, http.MethodPatch, "/v1/department-infos/"+strconv.Itoa(id), token, map[string]any{
+		"staffQuantity": 15,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("patch: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	departmentInfo := body["department_info"].(map[string]any)
+	if departmentInfo["staffQuantity"] != float64(15) || departmentInfo["version"] != float64(2) {
+		t.Errorf("patch: got %v; want staffQuantity 15 at version 2", departmentInfo)
+	}
+
+	status, _ = ts.do(t, http.MethodDelete, "/v1/department-infos/"+strconv.Itoa(id), token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("delete: got status %d; want %d", status, http.StatusOK)
+	}
+	status, _ = ts.do(t, http.MethodGet, "/v1/department-infos/"+strconv.Itoa(id), token, nil)
+	if status != http.StatusNotFound {
+		t.Errorf("get after delete: got status %d; want %d", status, http.StatusNotFound)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 )
 
 // The logError() method is a generic helper for logging an error message.
@@ -46,7 +48,7 @@ func ErrorResponse(w http.ResponseWriter, r *http.Request, status int, message i
 	err := WriteJSON(w, status, env, nil)
 	if err != nil {
 		//App.logError(r, err)
-		fmt.Errorf("request_method %v, request_url %v", r.Method, r.URL.String())
+		log.Printf("request_method %v, request_url %v: %v", r.Method, r.URL.String(), err)
 		w.WriteHeader(500)
 	}
 }
@@ -56,8 +58,7 @@ func ErrorResponse(w http.ResponseWriter, r *http.Request, status int, message i
 // errorResponse() helper to send a 500 Internal Server Error status code and JSON
 // response (containing a generic error message) to the client.
 func (app *application) serverErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
-	//App.logError(r, err)
-	fmt.Errorf("request_method %v, request_url %v", r.Method, r.URL.String())
+	app.logError(r, err)
 	message := "the server encountered a problem and could not process your request"
 	app.errorResponse(w, r, http.StatusInternalServerError, message)
 }
@@ -113,22 +114,50 @@ func EditConflictResponse(w http.ResponseWriter, r *http.Request) {
 	ErrorResponse(w, r, http.StatusConflict, message)
 }
 
+// The preconditionFailedResponse() method is used when the If-Match header sent by
+// the client no longer matches the current version of the record.
+func (app *application) preconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the record has been modified since it was retrieved, please fetch it again"
+	app.errorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
+func PreconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the record has been modified since it was retrieved, please fetch it again"
+	ErrorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
 func (app *application) invalidCredentialsResponse(w http.ResponseWriter, r *http.Request) {
 	message := "invalid authentication credentials"
 	app.errorResponse(w, r, http.StatusUnauthorized, message)
 }
 
-func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
+// The rateLimitExceededResponse() method sends a 429 Too Many Requests response with a
+// Retry-After header telling the client how many seconds to wait before retrying.
+func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request, retryAfter int) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
 	message := "rate limit exceeded"
 	app.errorResponse(w, r, http.StatusTooManyRequests, message)
 }
 
+// The loginThrottledResponse() method sends a 429 Too Many Requests response when too
+// many logins have failed for the email or the client address.
+func (app *application) loginThrottledResponse(w http.ResponseWriter, r *http.Request, retryAfter int) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	message := "too many failed login attempts, please try again later"
+	app.errorResponse(w, r, http.StatusTooManyRequests, message)
+}
+
 func (app *application) invalidAuthenticationTokenResponse(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("WWW-Authenticate", "Bearer")
 	message := "invalid or missing authentication token"
 	app.errorResponse(w, r, http.StatusUnauthorized, message)
 }
 
+func (app *application) invalidRefreshTokenResponse(w http.ResponseWriter, r *http.Request) {
+	message := "invalid or expired refresh token"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
+
 func (app *application) authenticationRequiredResponse(w http.ResponseWriter, r *http.Request) {
 	message := "you must be authenticated to access this resource"
 	app.errorResponse(w, r, http.StatusUnauthorized, message)
@@ -143,3 +172,8 @@ func (app *application) notPermittedResponse(w http.ResponseWriter, r *http.Requ
 	message := "your user account doesn't have the necessary permissions to access this resource"
 	app.errorResponse(w, r, http.StatusForbidden, message)
 }
+
+func (app *application) mfaRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	message := "your user account must enable two-factor authentication to access this resource"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"github.com/bxiit/greenlight/internal/data"
+	pb "github.com/bxiit/greenlight/rpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+)
+
+// grpcServer returns the gRPC server with every service registered, together with
+// the health server whose status is driven by watchDatabaseHealth(). Reflection is
+// only served in the development environment.
+func (app *application) grpcServer() (*grpc.Server, *health.Server) {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(app.authUnaryInterceptor),
+		grpc.ChainStreamInterceptor(app.authStreamInterceptor),
+	)
+	pb.RegisterUserServiceServer(srv, &UserService{app: app})
+	pb.RegisterModuleInfoServiceServer(srv, &ModuleInfoService{
+		Repo:    app.models.ModuleInfos,
+		Changes: app.moduleInfoChanges,
+		app:     app,
+	})
+	pb.RegisterDepartmentInfoServiceServer(srv, &DepartmentInfoService{
+		Repo: app.models.DepartmentInfos,
+		app:  app,
+	})
+
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	if app.config.Env == "development" {
+		reflection.Register(srv)
+	}
+	return srv, healthSrv
+}
+
+// stopGRPC waits for in-flight RPCs to finish like http.Server.Shutdown() does, and
+// cuts them off once the context is done.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// The helpers below are the gRPC counterparts of the ones in errors.go: they turn an
+// outcome into a status error with the same message the HTTP API would send.
+
+// serverErrorStatus logs the error together with the RPC it happened in.
+func (app *application) serverErrorStatus(ctx context.Context, err error) error {
+	method, _ := grpc.Method(ctx)
+	app.logger.PrintError(err, map[string]string{
+		"grpc_method": method,
+	})
+	return status.Error(codes.Internal, "the server encountered a problem and could not process your request")
+}
+
+func invalidAuthenticationTokenStatus() error {
+	return status.Error(codes.Unauthenticated, "invalid or missing authentication token")
+}
+
+func authenticationRequiredStatus() error {
+	return status.Error(codes.Unauthenticated, "you must be authenticated to access this resource")
+}
+
+func inactiveAccountStatus() error {
+	return status.Error(codes.PermissionDenied, "your user account must be activated to access this resource")
+}
+
+func notPermittedStatus() error {
+	return status.Error(codes.PermissionDenied, "your user account doesn't have the necessary permissions to access this resource")
+}
+
+func mfaRequiredStatus() error {
+	return status.Error(codes.PermissionDenied, "your user account must enable two-factor authentication to access this resource")
+}
+
+func notFoundStatus() error {
+	return status.Error(codes.NotFound, "the requested resource could not be found")
+}
+
+func duplicateEmailStatus() error {
+	return status.Error(codes.AlreadyExists, "a user with this email address already exists")
+}
+
+func editConflictStatus() error {
+	return status.Error(codes.Aborted, "unable to update the record due to an edit conflict, please try again")
+}
+
+func preconditionFailedStatus() error {
+	return status.Error(codes.FailedPrecondition, "the record has been modified since it was retrieved, please fetch it again")
+}
+
+// failedValidationStatus reports every validator error as a field violation of a
+// BadRequest detail, so that clients can tell the fields apart as they can with the
+// "error" object of a 422 response.
+func failedValidationStatus(errors map[string]string) error {
+	fields := make([]string, 0, len(errors))
+	for field := range errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: errors[field],
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, "the request failed validation")
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func userInfoToProto(userInfo *data.UserInfo) *pb.UserInfo {
+	return &pb.UserInfo{
+		Id:        userInfo.ID,
+		CreatedAt: timestamppb.New(userInfo.CreatedAt),
+		UpdatedAt: timestamppb.New(userInfo.UpdatedAt),
+		Name:      userInfo.Name,
+		Surname:   userInfo.Surname,
+		Email:     userInfo.Email,
+		Role:      userInfo.Role,
+		Activated: userInfo.Activated,
+	}
+}
+
+func metadataToProto(metadata data.Metadata) *pb.Metadata {
+	return &pb.Metadata{
+		CurrentPage:  int32(metadata.CurrentPage),
+		PageSize:     int32(metadata.PageSize),
+		FirstPage:    int32(metadata.FirstPage),
+		LastPage:     int32(metadata.LastPage),
+		TotalRecords: int32(metadata.TotalRecords),
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	pb "github.com/bxiit/greenlight/rpc"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"strings"
+)
+
+// grpcMethodPermissions is the gRPC counterpart of the requirePermission() calls in
+// routes.go: it names the permission code each method needs. Every method has to be
+// listed; one that isn't is refused to everybody, so that a new RPC can't be exposed
+// by forgetting to add it here.
+var grpcMethodPermissions = map[string]string{
+	pb.UserService_InsertUser_FullMethodName: "user_info:write",
+	pb.UserService_GetUser_FullMethodName:    "user_info:read",
+	pb.UserService_ListUsers_FullMethodName:  "user_info:read",
+	pb.UserService_UpdateUser_FullMethodName: "user_info:write",
+	pb.UserService_DeleteUser_FullMethodName: "user_info:write",
+
+	pb.ModuleInfoService_CreateModuleInfo_FullMethodName: "module_info:write",
+	pb.ModuleInfoService_GetModuleInfo_FullMethodName:    "module_info:read",
+	pb.ModuleInfoService_ListModuleInfos_FullMethodName:  "module_info:read",
+	pb.ModuleInfoService_UpdateModuleInfo_FullMethodName: "module_info:write",
+	pb.ModuleInfoService_DeleteModuleInfo_FullMethodName: "module_info:write",
+	pb.ModuleInfoService_WatchModuleInfos_FullMethodName: "module_info:read",
+
+	pb.DepartmentInfoService_CreateDepartmentInfo_FullMethodName: "department_info:write",
+	pb.DepartmentInfoService_GetDepartmentInfo_FullMethodName:    "department_info:read",
+	pb.DepartmentInfoService_ListDepartmentInfos_FullMethodName:  "department_info:read",
+	pb.DepartmentInfoService_UpdateDepartmentInfo_FullMethodName: "department_info:write",
+	pb.DepartmentInfoService_DeleteDepartmentInfo_FullMethodName: "department_info:write",
+}
+
+// grpcPublicMethods are served without authentication: health checks come from load
+// balancers and reflection from tooling, neither of which has a token.
+var grpcPublicMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_Watch_FullMethodName: true,
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      true,
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: true,
+}
+
+func (app *application) authUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := app.authorizeRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (app *application) authStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := app.authorizeRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authServerStream hands the context carrying the user info to stream handlers.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizeRPC does for a call what authenticate and requirePermission do for a
+// request: it resolves the bearer token in the "authorization" metadata, stores the
+// user info in the returned context and checks the permission the method needs.
+func (app *application) authorizeRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	if grpcPublicMethods[fullMethod] {
+		return ctx, nil
+	}
+
+	code, declared := grpcMethodPermissions[fullMethod]
+	if !declared {
+		return nil, notPermittedStatus()
+	}
+
+	userInfo, permissions, err := app.authenticateRPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = app.grpcContextSetUserInfo(ctx, userInfo)
+	if permissions != nil {
+		ctx = app.grpcContextSetPermissions(ctx, permissions)
+	}
+
+	if userInfo.IsAnonymous() {
+		return nil, authenticationRequiredStatus()
+	}
+	if !userInfo.Activated {
+		return nil, inactiveAccountStatus()
+	}
+
+	// Permissions carried by a signed access token are used as they are.
+	if permissions == nil {
+		missing, err := app.mfaMissing(userInfo.ID)
+		if err != nil {
+			return nil, app.serverErrorStatus(ctx, err)
+		}
+		if missing {
+			return nil, mfaRequiredStatus()
+		}
+		permissions, err = app.models.Permissions.GetAllForUserInfo(userInfo.ID)
+		if err != nil {
+			return nil, app.serverErrorStatus(ctx, err)
+		}
+	}
+	if !permissions.Include(code) {
+		return nil, notPermittedStatus()
+	}
+	return ctx, nil
+}
+
+// authenticateRPC returns the anonymous user info if the call carries no token. The
+// permissions are only returned in jwt mode, where they come from the token's claims.
+func (app *application) authenticateRPC(ctx context.Context) (*data.UserInfo, data.Permissions, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return data.AnonymousUserInfo, nil, nil
+	}
+
+	headerParts := strings.Split(values[0], " ")
+	if len(values) != 1 || len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return nil, nil, invalidAuthenticationTokenStatus()
+	}
+	token := headerParts[1]
+
+	if app.jwtKeys != nil {
+		claims, err := app.jwtKeys.Parse(token)
+		if err != nil {
+			return nil, nil, invalidAuthenticationTokenStatus()
+		}
+		userID, err := claims.UserID()
+		if err != nil {
+			return nil, nil, invalidAuthenticationTokenStatus()
+		}
+		userInfo := &data.UserInfo{
+			ID:        userID,
+			Role:      claims.Role,
+			Activated: claims.Activated,
+		}
+		// An empty list still means the token decides: MFA-pending users get
+		// one, and they mustn't fall back to the database.
+		permissions := data.Permissions{}
+		permissions = append(permissions, claims.Permissions...)
+		return userInfo, permissions, nil
+	}
+
+	v := validator.New()
+	if data.ValidateTokenPlaintext(v, token); !v.Valid() {
+		return nil, nil, invalidAuthenticationTokenStatus()
+	}
+
+	userInfo, err := app.models.UserInfos.GetForToken(data.ScopeAuthentication, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, nil, invalidAuthenticationTokenStatus()
+		default:
+			return nil, nil, app.serverErrorStatus(ctx, err)
+		}
+	}
+	return userInfo, nil, nil
+}
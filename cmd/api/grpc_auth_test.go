@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+// Every registered method has to name its permission in grpcMethodPermissions or be
+// public, or authorizeRPC refuses it to everybody.
+func TestGRPCMethodPermissionsDeclared(t *testing.T) {
+	app := &application{config: Config{Env: "development"}}
+	srv, _ := app.grpcServer()
+	for service, info := range srv.GetServiceInfo() {
+		for _, method := range info.Methods {
+			fullMethod := "/" + service + "/" + method.Name
+			if grpcPublicMethods[fullMethod] {
+				continue
+			}
+			if _, ok := grpcMethodPermissions[fullMethod]; !ok {
+				t.Errorf("%s is missing from grpcMethodPermissions", fullMethod)
+			}
+		}
+	}
+}
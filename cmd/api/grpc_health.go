@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// grpcHealthInterval is how often the database is pinged to refresh the status
+// served by grpc.health.v1.Health.
+const grpcHealthInterval = 5 * time.Second
+
+// watchDatabaseHealth reports every service as SERVING while the database answers a
+// ping and as NOT_SERVING while it doesn't, until ctx is cancelled. Without a
+// database there is nothing which could fail, so it reports SERVING once and returns.
+func (app *application) watchDatabaseHealth(ctx context.Context, healthSrv *health.Server, services []string) {
+	setStatus := func(status healthpb.HealthCheckResponse_ServingStatus) {
+		// The empty name is the status of the server as a whole.
+		healthSrv.SetServingStatus("", status)
+		for _, service := range services {
+			healthSrv.SetServingStatus(service, status)
+		}
+	}
+
+	if app.db == nil {
+		setStatus(healthpb.HealthCheckResponse_SERVING)
+		return
+	}
+
+	ticker := time.NewTicker(grpcHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, time.Second)
+		err := app.db.PingContext(pingCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			app.logger.PrintError(err, map[string]string{
+				"check": "grpc health",
+			})
+			setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		} else {
+			setStatus(healthpb.HealthCheckResponse_SERVING)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
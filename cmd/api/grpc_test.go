@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"github.com/bxiit/greenlight/internal/jsonlog"
+	pb "github.com/bxiit/greenlight/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+)
+
+// newTestGRPCConn serves app.grpcServer() over an in-memory listener and returns a
+// client connection to it, so that the services can be tested without opening a
+// port. Everything is torn down when the test ends.
+func newTestGRPCConn(t *testing.T, app *application) *grpc.ClientConn {
+	t.Helper()
+
+	if app.logger == nil {
+		app.logger = jsonlog.New(io.Discard, jsonlog.LevelOff)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv, healthSrv := app.grpcServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	services := make([]string, 0)
+	for service := range srv.GetServiceInfo() {
+		services = append(services, service)
+	}
+	// Set the initial status before any call can check it.
+	if app.db == nil {
+		app.watchDatabaseHealth(ctx, healthSrv, services)
+	} else {
+		go app.watchDatabaseHealth(ctx, healthSrv, services)
+	}
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		srv.Stop()
+	})
+	return conn
+}
+
+// withBearerToken returns a context which sends token the way clients authenticate
+// RPCs.
+func withBearerToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestGRPCHealth(t *testing.T) {
+	conn := newTestGRPCConn(t, &application{})
+	client := healthpb.NewHealthClient(conn)
+
+	for _, service := range []string{"", "rpc.ModuleInfoService"} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", service, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q) = %s; want SERVING", service, resp.Status)
+		}
+	}
+}
+
+func TestGRPCReflection(t *testing.T) {
+	tests := []struct {
+		env     string
+		enabled bool
+	}{
+		{env: "development", enabled: true},
+		{env: "production", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			conn := newTestGRPCConn(t, &application{config: Config{Env: tt.env}})
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := stream.Recv()
+
+			if !tt.enabled {
+				if status.Code(err) != codes.Unimplemented {
+					t.Fatalf("got %v; want Unimplemented", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, service := range resp.GetListServicesResponse().GetService() {
+				if service.Name == "rpc.UserService" {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("rpc.UserService is not listed")
+			}
+		})
+	}
+}
+
+func TestGRPCAuthenticationRequired(t *testing.T) {
+	conn := newTestGRPCConn(t, &application{})
+	client := pb.NewModuleInfoServiceClient(conn)
+
+	_, err := client.ListModuleInfos(context.Background(), &pb.ListModuleInfosRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("without a token: got %v; want Unauthenticated", err)
+	}
+
+	_, err = client.ListModuleInfos(withBearerToken(context.Background(), "abc"), &pb.ListModuleInfosRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("with a malformed token: got %v; want Unauthenticated", err)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// lockoutPolicy is the parsed form of the Lockout config block.
+type lockoutPolicy struct {
+	enabled       bool
+	maxFailures   int
+	ipMaxFailures int
+	duration      time.Duration
+	baseDelay     time.Duration
+	maxDelay      time.Duration
+}
+
+func newLockoutPolicy(cfg Config) (lockoutPolicy, error) {
+	policy := lockoutPolicy{
+		enabled:       cfg.Lockout.Enabled,
+		maxFailures:   cfg.Lockout.MaxFailures,
+		ipMaxFailures: cfg.Lockout.IpMaxFailures,
+	}
+	if !policy.enabled {
+		return policy, nil
+	}
+
+	var err error
+	policy.duration, err = time.ParseDuration(cfg.Lockout.Duration)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	policy.baseDelay, err = time.ParseDuration(cfg.Lockout.BaseDelay)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	policy.maxDelay, err = time.ParseDuration(cfg.Lockout.MaxDelay)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	if policy.maxFailures < 1 || policy.ipMaxFailures < 1 {
+		return lockoutPolicy{}, errors.New("lockout maxFailures and ipMaxFailures must be positive")
+	}
+	return policy, nil
+}
+
+// delay returns how long to wait after the given number of consecutive failures. It
+// doubles with every failure, starting at baseDelay and capped at maxDelay.
+func (p lockoutPolicy) delay(failures int) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+	d := float64(p.baseDelay) * math.Pow(2, float64(failures-1))
+	if d > float64(p.maxDelay) {
+		return p.maxDelay
+	}
+	return time.Duration(d)
+}
+
+// retryAt returns the earliest time another login may be tried for an email key.
+func (p lockoutPolicy) retryAt(failure *data.LoginFailure) time.Time {
+	retryAt := failure.LastFailure.Add(p.delay(failure.Failures))
+	if failure.LockedUntil.After(retryAt) {
+		retryAt = failure.LockedUntil
+	}
+	return retryAt
+}
+
+func emailLoginKey(email string) string {
+	return "email:" + strings.ToLower(email)
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginRetryAfter returns how long the client has to wait before it may try to log in
+// with the email again. Zero means it may try now. The answer only depends on earlier
+// failures for the email and client address, never on whether the account exists.
+// Only the email backs off between failures; the client address, which may be shared
+// by many users, only counts once it is locked.
+func (app *application) loginRetryAfter(r *http.Request, email string) (time.Duration, error) {
+	if !app.lockout.enabled {
+		return 0, nil
+	}
+
+	keys := []struct {
+		key     string
+		backoff bool
+	}{
+		{emailLoginKey(email), true},
+		{ipLoginKey(app.clientIP(r)), false},
+	}
+
+	var wait time.Duration
+	for _, k := range keys {
+		failure, err := app.models.LoginFailures.Get(k.key)
+		if err != nil {
+			if errors.Is(err, data.ErrRecordNotFound) {
+				continue
+			}
+			return 0, err
+		}
+		retryAt := failure.LockedUntil
+		if k.backoff {
+			retryAt = app.lockout.retryAt(failure)
+		}
+		if d := time.Until(retryAt); d > wait {
+			wait = d
+		}
+	}
+	return wait, nil
+}
+
+// recordLoginFailure counts a failed login for the email and the client address and
+// locks whichever of them has reached its limit. userInfo is nil when no account has
+// the email; the owner of an existing account is told by email when it gets locked.
+func (app *application) recordLoginFailure(r *http.Request, email string, userInfo *data.UserInfo) error {
+	if !app.lockout.enabled {
+		return nil
+	}
+
+	failure, err := app.models.LoginFailures.Record(emailLoginKey(email), app.lockout.duration)
+	if err != nil {
+		return err
+	}
+	if failure.Failures >= app.lockout.maxFailures && !failure.LockedUntil.After(time.Now()) {
+		lockedUntil := time.Now().Add(app.lockout.duration)
+		err = app.models.LoginFailures.Lock(failure.Key, lockedUntil)
+		if err != nil {
+			return err
+		}
+
+		if userInfo != nil {
+			app.background(func() {
+				data := map[string]any{
+					"failures":    failure.Failures,
+					"lockedUntil": lockedUntil.UTC().Format(time.RFC1123),
+				}
+				err := app.mailer.Send(userInfo.Email, "login_locked.tmpl", data)
+				if err != nil {
+					app.logger.PrintError(err, nil)
+				}
+			})
+		}
+	}
+
+	failure, err = app.models.LoginFailures.Record(ipLoginKey(app.clientIP(r)), app.lockout.duration)
+	if err != nil {
+		return err
+	}
+	if failure.Failures >= app.lockout.ipMaxFailures && !failure.LockedUntil.After(time.Now()) {
+		return app.models.LoginFailures.Lock(failure.Key, time.Now().Add(app.lockout.duration))
+	}
+	return nil
+}
+
+// UnlockUserInfoHandler lets an admin clear the failed logins and lock of the user info
+// named in the URL. The client address the logins came from is locked separately, for
+// example after many failures from behind a shared NAT, and stays locked unless it is
+// passed as well, as in ?ip=203.0.113.7.
+func (app *application) UnlockUserInfoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	qs := r.URL.Query()
+	v := validator.New()
+	ip := net.ParseIP(app.readString(qs, "ip", ""))
+	if v.Check(ip != nil || !qs.Has("ip"), "ip", "must be an IP address"); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	userInfo, err := app.models.UserInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	keys := []string{emailLoginKey(userInfo.Email)}
+	if ip != nil {
+		keys = append(keys, ipLoginKey(ip.String()))
+	}
+	for _, key := range keys {
+		err = app.models.LoginFailures.Reset(key)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "user info successfully unlocked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bxiit/greenlight/internal/data"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newLockoutTestServer returns a test server which locks an email after three failed
+// logins. The backoff between failures is too short to matter, apart from the
+// rounding of the stored timestamps which waitForRetry() takes care of.
+func newLockoutTestServer(t *testing.T) *testServer {
+	ts := newTestServer(t)
+	ts.app.lockout = lockoutPolicy{
+		enabled:       true,
+		maxFailures:   3,
+		ipMaxFailures: 100,
+		duration:      time.Hour,
+		baseDelay:     time.Nanosecond,
+		maxDelay:      time.Nanosecond,
+	}
+	return ts
+}
+
+// loginResult is the outcome of one POST /v1/tokens/authentication.
+type loginResult struct {
+	status     int
+	retryAfter string
+	body       map[string]any
+}
+
+func (ts *testServer) tryLogin(t *testing.T, email, password string) loginResult {
+	t.Helper()
+
+	js, err := json.Marshal(map[string]string{"email": email, "password": password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := ts.Client().Post(ts.URL+"/v1/tokens/authentication", "application/json", bytes.NewReader(js))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	result := loginResult{status: res.StatusCode, retryAfter: res.Header.Get("Retry-After")}
+	err = json.NewDecoder(res.Body).Decode(&result.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// waitForRetry sleeps until the backoff after the last failed login for the email is
+// over.
+func (ts *testServer) waitForRetry(t *testing.T, email string) {
+	t.Helper()
+
+	failure, err := ts.app.models.LoginFailures.Get(emailLoginKey(email))
+	if errors.Is(err, data.ErrRecordNotFound) {
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Until(ts.app.lockout.retryAt(failure)))
+}
+
+// failLogins tries to log in with a wrong password n times, waiting out the backoff
+// in between, followed by one more try once the email ought to be locked.
+func (ts *testServer) failLogins(t *testing.T, email string, n int) []loginResult {
+	t.Helper()
+
+	var results []loginResult
+	for i := 0; i < n; i++ {
+		ts.waitForRetry(t, email)
+		results = append(results, ts.tryLogin(t, email, "wrong-pa55word"))
+	}
+	return append(results, ts.tryLogin(t, email, "wrong-pa55word"))
+}
+
+func lockedMails(mails []sentMail) int {
+	count := 0
+	for _, mail := range mails {
+		if mail.Template == "login_locked.tmpl" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLoginLockout(t *testing.T) {
+	ts := newLockoutTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+
+	results := ts.failLogins(t, "alice@example.com", 3)
+	for i, result := range results[:3] {
+		if result.status != http.StatusUnauthorized {
+			t.Fatalf("failure %d: got status %d; want %d", i+1, result.status, http.StatusUnauthorized)
+		}
+	}
+
+	// Even the right password is refused while the email is locked.
+	for _, result := range []loginResult{results[3], ts.tryLogin(t, "alice@example.com", "pa55word-alice")} {
+		if result.status != http.StatusTooManyRequests {
+			t.Fatalf("got status %d; want %d", result.status, http.StatusTooManyRequests)
+		}
+		retryAfter, err := strconv.Atoi(result.retryAfter)
+		if err != nil || retryAfter < 3500 || retryAfter > 3601 {
+			t.Fatalf("got Retry-After %q; want about an hour", result.retryAfter)
+		}
+	}
+
+	if n := lockedMails(ts.sentTo("alice@example.com")); n != 1 {
+		t.Fatalf("got %d login_locked.tmpl emails; want 1", n)
+	}
+}
+
+// The responses don't tell whether an account has the email, and only the owner of an
+// existing account is told about the lock.
+func TestLoginLockoutUnknownEmail(t *testing.T) {
+	ts := newLockoutTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+
+	known := ts.failLogins(t, "alice@example.com", 3)
+	unknown := ts.failLogins(t, "nobody@example.com", 3)
+	for i := range known {
+		if fmt.Sprint(unknown[i].status, unknown[i].body) != fmt.Sprint(known[i].status, known[i].body) ||
+			(unknown[i].retryAfter == "") != (known[i].retryAfter == "") {
+			t.Fatalf("attempt %d: got %+v for an unknown email; want the same as %+v", i+1, unknown[i], known[i])
+		}
+	}
+
+	if mails := ts.sentTo("nobody@example.com"); len(mails) != 0 {
+		t.Fatalf("got %d emails to the unknown address; want none", len(mails))
+	}
+}
+
+// A client address doesn't back off, so one user's wrong password doesn't slow down
+// another user logging in from behind the same NAT.
+func TestLoginBackoffSharedAddress(t *testing.T) {
+	ts := newLockoutTestServer(t)
+	ts.app.lockout.baseDelay = time.Minute
+	ts.app.lockout.maxDelay = time.Minute
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	ts.createUserInfo(t, "bob@example.com", "pa55word-bob")
+
+	if result := ts.tryLogin(t, "bob@example.com", "wrong-pa55word"); result.status != http.StatusUnauthorized {
+		t.Fatalf("bob's wrong password: got status %d; want %d", result.status, http.StatusUnauthorized)
+	}
+	if result := ts.tryLogin(t, "alice@example.com", "pa55word-alice"); result.status != http.StatusCreated {
+		t.Fatalf("alice's login from the same address: got status %d; want %d", result.status, http.StatusCreated)
+	}
+	result := ts.tryLogin(t, "bob@example.com", "pa55word-bob")
+	if result.status != http.StatusTooManyRequests || result.retryAfter == "" {
+		t.Fatalf("bob's retry: got status %d, Retry-After %q; want %d", result.status, result.retryAfter, http.StatusTooManyRequests)
+	}
+}
+
+func TestUnlockUserInfo(t *testing.T) {
+	ts := newLockoutTestServer(t)
+	admin := ts.loginAsAdmin(t)
+	alice := ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+
+	ts.failLogins(t, "alice@example.com", 3)
+	if result := ts.tryLogin(t, "alice@example.com", "pa55word-alice"); result.status != http.StatusTooManyRequests {
+		t.Fatalf("login while locked: got status %d; want %d", result.status, http.StatusTooManyRequests)
+	}
+
+	status, _ := ts.do(t, http.MethodDelete, fmt.Sprintf("/v1/user-infos/%d/lockout", alice.ID), admin, nil)
+	if status != http.StatusOK {
+		t.Fatalf("unlock: got status %d; want %d", status, http.StatusOK)
+	}
+	if result := ts.tryLogin(t, "alice@example.com", "pa55word-alice"); result.status != http.StatusCreated {
+		t.Fatalf("login after the unlock: got status %d; want %d", result.status, http.StatusCreated)
+	}
+}
+
+func TestUnlockUserInfoClientAddress(t *testing.T) {
+	ts := newLockoutTestServer(t)
+	ts.app.lockout.maxFailures = 100
+	ts.app.lockout.ipMaxFailures = 2
+	admin := ts.loginAsAdmin(t)
+	alice := ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+
+	// Failures for other emails lock the client address, and with it alice.
+	ts.failLogins(t, "nobody@example.com", 2)
+	if result := ts.tryLogin(t, "alice@example.com", "pa55word-alice"); result.status != http.StatusTooManyRequests {
+		t.Fatalf("login from a locked address: got status %d; want %d", result.status, http.StatusTooManyRequests)
+	}
+
+	path := fmt.Sprintf("/v1/user-infos/%d/lockout", alice.ID)
+	tests := []struct {
+		query       string
+		unlock      int
+		afterUnlock int
+	}{
+		{"?ip=not-an-ip", http.StatusUnprocessableEntity, http.StatusTooManyRequests},
+		{"", http.StatusOK, http.StatusTooManyRequests},
+		{"?ip=127.0.0.1", http.StatusOK, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		status, _ := ts.do(t, http.MethodDelete, path+tt.query, admin, nil)
+		if status != tt.unlock {
+			t.Fatalf("unlock%s: got status %d; want %d", tt.query, status, tt.unlock)
+		}
+		if result := ts.tryLogin(t, "alice@example.com", "pa55word-alice"); result.status != tt.afterUnlock {
+			t.Fatalf("login after unlock%s: got status %d; want %d", tt.query, result.status, tt.afterUnlock)
+		}
+	}
+}
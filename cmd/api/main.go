@@ -3,19 +3,22 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/bxiit/greenlight/internal/jsonlog"
+	"github.com/bxiit/greenlight/internal/jwtauth"
 	"github.com/bxiit/greenlight/internal/mailer"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"net/http"
+	"net"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/data/memory"
 	// underscore (alias) is used to avoid go compiler complaining or erasing this
 	// library.
 	_ "github.com/lib/pq"
@@ -28,7 +31,16 @@ const version = "1.0.0"
 type Config struct {
 	Port int
 	Env  string
-	Db   struct {
+	// The gRPC services are served on their own port. 0, the default, leaves the
+	// gRPC server off.
+	Grpc struct {
+		Port int
+	}
+	// Backend is "postgres" (the default) or "memory", which keeps every record in
+	// memory for demos and loses them on shutdown. The other fields only apply to
+	// Postgres.
+	Db struct {
+		Backend      string
 		Dsn          string
 		MaxOpenConns int
 		MaxIdleConns int
@@ -38,6 +50,52 @@ type Config struct {
 		Enabled bool
 		Rps     float64
 		Burst   int
+		// Addresses or CIDR ranges of reverse proxies whose X-Forwarded-For
+		// header is trusted when identifying the client.
+		TrustedProxies []string
+	}
+	// Failed logins are tracked per email and per client address. After each
+	// failure for an email the next attempt has to wait BaseDelay, doubling up to
+	// MaxDelay, and after MaxFailures it is locked for Duration. A client address
+	// doesn't back off, so that users behind a shared NAT don't slow each other
+	// down, but is locked for Duration after IpMaxFailures.
+	Lockout struct {
+		Enabled       bool
+		MaxFailures   int
+		IpMaxFailures int
+		Duration      string
+		BaseDelay     string
+		MaxDelay      string
+	}
+	// Users holding one of these roles must enable two-factor authentication
+	// before any permission is granted to them.
+	Mfa struct {
+		RequiredRoles []string
+	}
+	// Hasher is "argon2id" (the default) or "bcrypt". Hashes made by the other one
+	// still verify and are replaced at the next successful login.
+	// Policy applies to new passwords. BreachedFile is a local copy of the Have I
+	// Been Pwned SHA-1 list: either a directory of "<PREFIX>.txt" buckets in the
+	// range format ("SUFFIX:COUNT" per line), or a single file of "HASH:COUNT" lines
+	// sorted by hash. Leave it empty to skip the check.
+	Password struct {
+		Hasher string
+		Argon2 struct {
+			Memory      uint32 // in KiB
+			Iterations  uint32
+			Parallelism uint8
+		}
+		BcryptCost int
+		Policy     struct {
+			MinLength          int
+			RequireUpper       bool
+			RequireLower       bool
+			RequireDigit       bool
+			RequireSymbol      bool
+			MaxRepeats         int
+			ForbidPersonalInfo bool
+		}
+		BreachedFile string
 	}
 	Smtp struct {
 		Host     string
@@ -46,15 +104,43 @@ type Config struct {
 		Password string
 		Sender   string
 	}
+	// Lifetimes of the access and refresh tokens issued at login, e.g. "15m" and
+	// "720h".
+	// Mode is "database" (the default) or "jwt". In jwt mode access tokens are signed
+	// with the active key of the keyset file and verified without a database query.
+	Tokens struct {
+		AccessTTL  string
+		RefreshTTL string
+		Mode       string
+		KeysetFile string
+	}
+	// How long to wait for in-flight requests and background tasks on shutdown,
+	// e.g. "20s".
+	ShutdownTimeout string
 }
 
 type application struct {
-	config Config
-	logger *jsonlog.Logger
-	models data.Models // hold new models in App
-	mailer mailer.Mailer
-	wg     sync.WaitGroup
-	gormDB *gorm.DB
+	config         Config
+	logger         *jsonlog.Logger
+	models         data.Models // hold new models in App
+	db             *sql.DB     // nil unless the models are backed by Postgres
+	mailer         mailSender
+	wg             sync.WaitGroup
+	gormDB         *gorm.DB
+	trustedProxies []*net.IPNet
+	accessTTL      time.Duration
+	refreshTTL     time.Duration
+	jwtKeys        *jwtauth.Keyset // nil unless tokens are in jwt mode
+	lockout        lockoutPolicy
+	rateLimiters   *clientLimiters
+	// moduleInfoChanges feeds the WatchModuleInfos streams.
+	moduleInfoChanges *moduleInfoFeed
+}
+
+// mailSender sends the templated emails. mailer.Mailer sends them over SMTP; tests
+// use a fake which records them instead.
+type mailSender interface {
+	Send(recipient, templateFile string, data any) error
 }
 
 type ApplicationX struct {
@@ -84,47 +170,157 @@ func main() {
 
 	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
 
-	db, err := openDB(cfg)
+	trustedProxies, err := parseTrustedProxies(cfg.Limiter.TrustedProxies)
 	if err != nil {
 		logger.PrintFatal(err, nil)
 	}
-	// Db will be closed before main function is completed.
-	defer db.Close()
 
-	gormDB, err := OpenGDB(db)
+	accessTTL, err := time.ParseDuration(cfg.Tokens.AccessTTL)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	refreshTTL, err := time.ParseDuration(cfg.Tokens.RefreshTTL)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+
+	lockout, err := newLockoutPolicy(cfg)
 	if err != nil {
 		logger.PrintFatal(err, nil)
 	}
 
-	logger.PrintInfo("database connection pool established", nil)
+	hasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	data.SetPasswordHasher(hasher)
+
+	policy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	data.SetPasswordPolicy(policy)
+
+	var jwtKeys *jwtauth.Keyset
+	switch cfg.Tokens.Mode {
+	case "", "database":
+	case "jwt":
+		jwtKeys, err = jwtauth.Load(cfg.Tokens.KeysetFile)
+		if err != nil {
+			logger.PrintFatal(err, nil)
+		}
+	default:
+		logger.PrintFatal(fmt.Errorf("unknown tokens mode %q", cfg.Tokens.Mode), nil)
+	}
+
+	var (
+		models data.Models
+		db     *sql.DB
+		gormDB *gorm.DB
+	)
+	switch cfg.Db.Backend {
+	case "", "postgres":
+		db, err = openDB(cfg)
+		if err != nil {
+			logger.PrintFatal(err, nil)
+		}
+		// Db will be closed before main function is completed.
+		defer db.Close()
+
+		gormDB, err = OpenGDB(db)
+		if err != nil {
+			logger.PrintFatal(err, nil)
+		}
+
+		models = data.NewModels(db) // data.NewModels() function to initialize a Models struct
+		logger.PrintInfo("database connection pool established", nil)
+	case "memory":
+		models = memory.NewModels(memory.NewDB())
+		logger.PrintInfo("using in-memory repositories, nothing will be persisted", nil)
+	default:
+		logger.PrintFatal(fmt.Errorf("unknown db backend %q", cfg.Db.Backend), nil)
+	}
 
 	app := &application{
 		config: cfg,
 		logger: logger,
-		models: data.NewModels(db), // data.NewModels() function to initialize a Models struct
+		models: models,
+		db:     db,
 		mailer: mailer.New(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.Smtp.Sender),
 		gormDB: gormDB,
+
+		trustedProxies: trustedProxies,
+		accessTTL:      accessTTL,
+		refreshTTL:     refreshTTL,
+		jwtKeys:        jwtKeys,
+		lockout:        lockout,
+		rateLimiters:   newClientLimiters(),
+
+		moduleInfoChanges: newModuleInfoFeed(),
 	}
 
-	go app.checkAndResendActivation()
+	// serve() blocks until the server has been shut down and every background task
+	// has finished, so the deferred db.Close() only runs once nothing uses the pool.
+	err = app.serve()
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+}
 
-	// Use the httprouter instance returned by App.routes() as the server handler.
-	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      app.routes(),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
+// newPasswordHasher builds the hasher for new passwords from the Password config
+// block. Zero values fall back to the defaults of the data package.
+func newPasswordHasher(cfg Config) (data.PasswordHasher, error) {
+	switch cfg.Password.Hasher {
+	case "", "argon2id":
+		hasher := data.DefaultArgon2idHasher
+		if cfg.Password.Argon2.Memory != 0 {
+			hasher.Memory = cfg.Password.Argon2.Memory
+		}
+		if cfg.Password.Argon2.Iterations != 0 {
+			hasher.Iterations = cfg.Password.Argon2.Iterations
+		}
+		if cfg.Password.Argon2.Parallelism != 0 {
+			hasher.Parallelism = cfg.Password.Argon2.Parallelism
+		}
+		return hasher, nil
+	case "bcrypt":
+		hasher := data.BcryptHasher{Cost: 12}
+		if cfg.Password.BcryptCost != 0 {
+			hasher.Cost = cfg.Password.BcryptCost
+		}
+		return hasher, nil
+	default:
+		return nil, fmt.Errorf("unknown password hasher %q", cfg.Password.Hasher)
 	}
+}
 
-	logger.PrintInfo("starting server", map[string]string{
-		"addr": srv.Addr,
-		"Env":  cfg.Env,
-	})
-	// reuse defined variable err
+// newPasswordPolicy builds the policy for new passwords from the Password config block
+// and opens the breached passwords file, if one is configured.
+func newPasswordPolicy(cfg Config) (data.PasswordPolicy, error) {
+	policy := data.PasswordPolicy{
+		MinLength:          cfg.Password.Policy.MinLength,
+		RequireUpper:       cfg.Password.Policy.RequireUpper,
+		RequireLower:       cfg.Password.Policy.RequireLower,
+		RequireDigit:       cfg.Password.Policy.RequireDigit,
+		RequireSymbol:      cfg.Password.Policy.RequireSymbol,
+		MaxRepeats:         cfg.Password.Policy.MaxRepeats,
+		ForbidPersonalInfo: cfg.Password.Policy.ForbidPersonalInfo,
+	}
+	if policy.MinLength < data.DefaultPasswordPolicy.MinLength {
+		policy.MinLength = data.DefaultPasswordPolicy.MinLength
+	}
+	if policy.MaxRepeats < 0 {
+		return data.PasswordPolicy{}, errors.New("password policy maxRepeats must not be negative")
+	}
 
-	err = srv.ListenAndServe()
-	logger.PrintFatal(err, nil)
+	if cfg.Password.BreachedFile != "" {
+		breached, err := data.OpenBreachedPasswords(cfg.Password.BreachedFile)
+		if err != nil {
+			return data.PasswordPolicy{}, err
+		}
+		policy.Breached = breached
+	}
+	return policy, nil
 }
 
 func openDB(cfg Config) (*sql.DB, error) {
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	"net/http"
+	"time"
+)
+
+// GetMeHandler returns the user info of the authenticated user.
+func (app *application) GetMeHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := app.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.writeJSON(w, http.StatusOK, Envelope{"user_info": userInfo}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// loadMe reads the full record of the authenticated user info. The one in the request
+// context only holds what the access token carries when tokens are in jwt mode. If it
+// returns false a response has already been sent.
+func (app *application) loadMe(w http.ResponseWriter, r *http.Request) (*data.UserInfo, bool) {
+	userInfo, err := app.models.UserInfos.Get(app.contextGetUserInfo(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.invalidAuthenticationTokenResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return nil, false
+	}
+	return userInfo, true
+}
+
+// PatchMeHandler lets the authenticated user change their own name, surname and
+// password. Changing the password or the email needs the current password. A new
+// email isn't saved straight away: a verification token is sent to the new address
+// and the change only happens once it is confirmed through UpdateMeEmailHandler.
+// Changing the password revokes every authentication and refresh token of the user,
+// including the one used for the request, as resetting it does.
+func (app *application) PatchMeHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := app.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Name            *string `json:"name"`
+		Surname         *string `json:"surname"`
+		Email           *string `json:"email"`
+		Password        *string `json:"password"`
+		CurrentPassword *string `json:"current_password"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if input.Name != nil {
+		data.ValidateUserInfoName(v, *input.Name)
+		userInfo.Name = *input.Name
+	}
+	if input.Surname != nil {
+		data.ValidateUserInfoSurname(v, *input.Surname)
+		userInfo.Surname = *input.Surname
+	}
+	if input.Email != nil {
+		data.ValidateEmail(v, *input.Email)
+	}
+	if input.Password != nil {
+		email := userInfo.Email
+		if input.Email != nil {
+			email = *input.Email
+		}
+		err = data.ValidatePasswordPolicy(v, *input.Password, email, userInfo.Name, userInfo.Surname)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+	if input.Email != nil || input.Password != nil {
+		v.Check(input.CurrentPassword != nil && *input.CurrentPassword != "", "current_password", "must be provided")
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	if input.CurrentPassword != nil && (input.Email != nil || input.Password != nil) {
+		match, _, err := userInfo.PasswordHashed.Matches(*input.CurrentPassword)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		if !match {
+			v.AddError("current_password", "is incorrect")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+	}
+
+	if input.Email != nil && *input.Email != userInfo.Email {
+		_, err := app.models.UserInfos.GetByEmail(*input.Email)
+		switch {
+		case err == nil:
+			v.AddError("email", "a user with this email address already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		case !errors.Is(err, data.ErrRecordNotFound):
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	if input.Password != nil {
+		err = userInfo.PasswordHashed.Set(*input.Password)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	err = app.models.UserInfos.Update(userInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if input.Password != nil {
+		err = app.models.Tokens.DeleteAllForUser(data.ScopeAuthentication, userInfo.ID)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		err = app.models.Tokens.DeleteAllForUser(data.ScopeRefresh, userInfo.ID)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	env := Envelope{"user_info": userInfo}
+
+	if input.Email != nil && *input.Email != userInfo.Email {
+		// Only one email change can be pending at a time.
+		err = app.models.Tokens.DeleteAllForUser(data.ScopeEmailChange, userInfo.ID)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		token, err := app.models.EmailChanges.New(userInfo.ID, *input.Email, 24*time.Hour)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		newEmail := *input.Email
+		app.background(func() {
+			data := map[string]any{
+				"emailChangeToken": token.Plaintext,
+			}
+			err := app.mailer.Send(newEmail, "token_email_change.tmpl", data)
+			if err != nil {
+				app.logger.PrintError(err, nil)
+			}
+		})
+
+		env["message"] = "an email will be sent to the new address containing instructions to confirm the change"
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateMeEmailHandler confirms an email change with the token that was sent to the
+// new address.
+func (app *application) UpdateMeEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		TokenPlaintext string `json:"token"`
+	}
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if data.ValidateTokenPlaintext(v, input.TokenPlaintext); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	userInfo, err := app.models.UserInfos.GetForToken(data.ScopeEmailChange, input.TokenPlaintext)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("token", "invalid or expired email change token")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	newEmail, err := app.models.EmailChanges.GetNewEmail(input.TokenPlaintext)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("token", "invalid or expired email change token")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	userInfo.Email = newEmail
+
+	err = app.models.UserInfos.Update(userInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateEmail):
+			v.AddError("email", "a user with this email address already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeEmailChange, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"user_info": userInfo}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Changing the email or the password needs the current password, other fields don't.
+func TestPatchMeCurrentPassword(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	token := ts.login(t, "alice@example.com", "pa55word-alice")
+
+	tests := []struct {
+		name  string
+		input map[string]string
+		want  int
+	}{
+		{"email without the current password", map[string]string{"email": "alice@example.org"}, http.StatusUnprocessableEntity},
+		{"password without the current password", map[string]string{"password": "new-pa55word-alice"}, http.StatusUnprocessableEntity},
+		{"email with a wrong current password", map[string]string{"email": "alice@example.org", "current_password": "wrong-pa55word"}, http.StatusUnprocessableEntity},
+		{"password with a wrong current password", map[string]string{"password": "new-pa55word-alice", "current_password": "wrong-pa55word"}, http.StatusUnprocessableEntity},
+		{"name", map[string]string{"name": "Alice"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		status, body := ts.do(t, http.MethodPatch, "/v1/me", token, tt.input)
+		if status != tt.want {
+			t.Fatalf("%s: got status %d; want %d: %v", tt.name, status, tt.want, body)
+		}
+	}
+
+	status, body := ts.do(t, http.MethodGet, "/v1/me", token, nil)
+	userInfo, _ := body["user_info"].(map[string]any)
+	if status != http.StatusOK || userInfo["name"] != "Alice" || userInfo["email"] != "alice@example.com" {
+		t.Fatalf("GET /v1/me: got status %d, %v; want the new name and the old email", status, body)
+	}
+	if len(ts.sentTo("alice@example.org")) != 0 {
+		t.Fatal("got an email change token after a refused change")
+	}
+}
+
+// A new email is only saved once the token sent to it is confirmed.
+func TestPatchMeEmail(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	ts.createUserInfo(t, "bob@example.com", "pa55word-bob")
+	token := ts.login(t, "alice@example.com", "pa55word-alice")
+
+	status, body := ts.do(t, http.MethodPatch, "/v1/me", token, map[string]string{
+		"email":            "bob@example.com",
+		"current_password": "pa55word-alice",
+	})
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("change to a taken email: got status %d; want %d: %v", status, http.StatusUnprocessableEntity, body)
+	}
+
+	status, body = ts.do(t, http.MethodPatch, "/v1/me", token, map[string]string{
+		"email":            "alice@example.org",
+		"current_password": "pa55word-alice",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("change email: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	if userInfo, _ := body["user_info"].(map[string]any); userInfo["email"] != "alice@example.com" {
+		t.Fatalf("got %v; want the old email until the change is confirmed", body)
+	}
+
+	mails := ts.sentTo("alice@example.org")
+	if len(mails) != 1 || mails[0].Template != "token_email_change.tmpl" {
+		t.Fatalf("got %+v; want one token_email_change.tmpl email", mails)
+	}
+	changeToken, _ := mails[0].Data.(map[string]any)["emailChangeToken"].(string)
+
+	status, body = ts.do(t, http.MethodPut, "/v1/me/email", "", map[string]string{"token": changeToken})
+	if status != http.StatusOK {
+		t.Fatalf("confirm: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	status, body = ts.do(t, http.MethodGet, "/v1/me", token, nil)
+	if userInfo, _ := body["user_info"].(map[string]any); status != http.StatusOK || userInfo["email"] != "alice@example.org" {
+		t.Fatalf("GET /v1/me after the confirmation: got status %d, %v; want the new email", status, body)
+	}
+
+	status, _ = ts.do(t, http.MethodPut, "/v1/me/email", "", map[string]string{"token": changeToken})
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("confirm again: got status %d; want %d", status, http.StatusUnprocessableEntity)
+	}
+}
+
+// Changing the password signs the user out of every session.
+func TestPatchMePassword(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	token := ts.login(t, "alice@example.com", "pa55word-alice")
+
+	status, body := ts.do(t, http.MethodPost, "/v1/tokens/authentication", "", map[string]string{
+		"email":    "alice@example.com",
+		"password": "pa55word-alice",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("second login: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	otherToken := tokenIn(t, body, "authentication_token")
+	refreshToken := tokenIn(t, body, "refresh_token")
+
+	status, body = ts.do(t, http.MethodPatch, "/v1/me", token, map[string]string{
+		"password":         "new-pa55word-alice",
+		"current_password": "pa55word-alice",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("change password: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+
+	for _, access := range []string{token, otherToken} {
+		status, _ = ts.do(t, http.MethodGet, "/v1/me", access, nil)
+		if status != http.StatusUnauthorized {
+			t.Fatalf("GET /v1/me with a token from before the change: got status %d; want %d", status, http.StatusUnauthorized)
+		}
+	}
+	status, _ = ts.do(t, http.MethodPost, "/v1/tokens/refresh", "", map[string]string{"refresh_token": refreshToken})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("refresh with a token from before the change: got status %d; want %d", status, http.StatusUnauthorized)
+	}
+
+	status, _ = ts.do(t, http.MethodPost, "/v1/tokens/authentication", "", map[string]string{
+		"email":    "alice@example.com",
+		"password": "pa55word-alice",
+	})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("login with the old password: got status %d; want %d", status, http.StatusUnauthorized)
+	}
+	ts.login(t, "alice@example.com", "new-pa55word-alice")
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	"math"
+	"net/http"
+	"time"
+)
+
+// StartMFAHandler generates a new TOTP secret for the authenticated user and returns
+// it with its provisioning URI. Two-factor authentication is only switched on once
+// the first code is confirmed through ConfirmMFAHandler.
+func (app *application) StartMFAHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := app.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		CurrentPassword string `json:"current_password"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if !app.checkCurrentPassword(w, r, v, userInfo, input.CurrentPassword) {
+		return
+	}
+
+	mfa, err := app.models.MFA.Get(userInfo.ID)
+	if err != nil && !errors.Is(err, data.ErrRecordNotFound) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if mfa != nil && mfa.Enabled {
+		v.AddError("mfa", "is already enabled")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	secret, uri, err := data.GenerateTOTPKey(userInfo.Email)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.MFA.SetSecret(userInfo.ID, secret)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := Envelope{"mfa": map[string]string{
+		"secret":           secret,
+		"provisioning_uri": uri,
+	}}
+	err = app.writeJSON(w, http.StatusCreated, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConfirmMFAHandler switches two-factor authentication on once the user sends a valid
+// code for the secret from StartMFAHandler. The recovery codes are only ever shown in
+// this response.
+func (app *application) ConfirmMFAHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo := app.contextGetUserInfo(r)
+
+	var input struct {
+		Code string `json:"code"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if data.ValidateTOTPCode(v, input.Code); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	mfa, err := app.models.MFA.Get(userInfo.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("mfa", "enrollment must be started first")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	if mfa.Enabled {
+		v.AddError("mfa", "is already enabled")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	ok, err := app.checkSecondFactor(mfa, input.Code, "")
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !ok {
+		v.AddError("code", "is incorrect")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	codes, err := data.GenerateRecoveryCodes()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.MFA.Enable(userInfo.ID, codes)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"recovery_codes": codes}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DisableMFAHandler switches two-factor authentication off. It needs both the current
+// password and a code (or a recovery code).
+func (app *application) DisableMFAHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := app.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		CurrentPassword string `json:"current_password"`
+		Code            string `json:"code"`
+		RecoveryCode    string `json:"recovery_code"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(input.Code != "" || input.RecoveryCode != "", "code", "must be provided")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+	if !app.checkCurrentPassword(w, r, v, userInfo, input.CurrentPassword) {
+		return
+	}
+
+	mfa, err := app.models.MFA.Get(userInfo.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("mfa", "is not enabled")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	ok, err = app.checkSecondFactor(mfa, input.Code, input.RecoveryCode)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !ok {
+		v.AddError("code", "is incorrect")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.MFA.Disable(userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "two-factor authentication successfully disabled"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateMFAAuthenticationTokenHandler is the second step of logging in for user infos
+// with two-factor authentication. It exchanges the mfa-pending token returned by
+// CreateAuthenticationTokenHandlerUserInfo and a code (or a recovery code) for the
+// access and refresh tokens.
+func (app *application) CreateMFAAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		MFAToken     string `json:"mfa_token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	data.ValidateTokenPlaintext(v, input.MFAToken)
+	v.Check(input.Code != "" || input.RecoveryCode != "", "code", "must be provided")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	userInfo, err := app.models.UserInfos.GetForToken(data.ScopeMFAPending, input.MFAToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.invalidCredentialsResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	// Codes are guessable, so the same backoff and lockout as for passwords applies.
+	wait, err := app.loginRetryAfter(r, userInfo.Email)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if wait > 0 {
+		app.loginThrottledResponse(w, r, int(math.Ceil(wait.Seconds())))
+		return
+	}
+
+	mfa, err := app.models.MFA.Get(userInfo.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.invalidCredentialsResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	// The secret of an enrollment which hasn't been confirmed yet, e.g. one started
+	// again after the mfa-pending token was issued, mustn't log anybody in.
+	if !mfa.Enabled {
+		app.invalidCredentialsResponse(w, r)
+		return
+	}
+
+	ok, err := app.checkSecondFactor(mfa, input.Code, input.RecoveryCode)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !ok {
+		app.failedLoginResponse(w, r, userInfo.Email, userInfo)
+		return
+	}
+
+	err = app.models.LoginFailures.Reset(emailLoginKey(userInfo.Email))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeMFAPending, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.issueTokenPair(w, r, userInfo)
+}
+
+// checkSecondFactor verifies a TOTP code, or a recovery code if no code is given. A
+// code is accepted once; a recovery code is used up.
+func (app *application) checkSecondFactor(mfa *data.MFA, code, recoveryCode string) (bool, error) {
+	if code == "" {
+		return app.models.MFA.UseRecoveryCode(mfa.UserID, recoveryCode)
+	}
+
+	step, ok, err := data.MatchTOTP(mfa.Secret, code, time.Now())
+	if err != nil || !ok {
+		return false, err
+	}
+	return app.models.MFA.UseStep(mfa.UserID, step)
+}
+
+// checkCurrentPassword sends a validation error and returns false unless the password
+// is the current password of the user info.
+func (app *application) checkCurrentPassword(w http.ResponseWriter, r *http.Request, v *validator.Validator, userInfo *data.UserInfo, password string) bool {
+	if v.Check(password != "", "current_password", "must be provided"); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return false
+	}
+
+	match, _, err := userInfo.PasswordHashed.Matches(password)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+	if !match {
+		v.AddError("current_password", "is incorrect")
+		app.failedValidationResponse(w, r, v.Errors)
+		return false
+	}
+	return true
+}
+
+// mfaMissing reports whether the user info holds a role which requires two-factor
+// authentication without having enabled it. Such users keep access to /v1/me so that
+// they can enroll, but no permission is granted until they do.
+func (app *application) mfaMissing(userID int64) (bool, error) {
+	if len(app.config.Mfa.RequiredRoles) == 0 {
+		return false, nil
+	}
+	return app.models.MFA.MissingForRoles(userID, app.config.Mfa.RequiredRoles)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"github.com/pquerna/otp/totp"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// totpCode returns the code of the secret at the time.
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	code, err := totp.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// enrollMFA turns two-factor authentication on through /v1/me/mfa and returns the
+// secret and the recovery codes.
+func (ts *testServer) enrollMFA(t *testing.T, token, password string) (string, []any) {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodPost, "/v1/me/mfa", token, map[string]string{"current_password": password})
+	if status != http.StatusCreated {
+		t.Fatalf("start enrollment: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	secret, _ := body["mfa"].(map[string]any)["secret"].(string)
+
+	status, body = ts.do(t, http.MethodPut, "/v1/me/mfa", token, map[string]string{"code": totpCode(t, secret, time.Now())})
+	if status != http.StatusOK {
+		t.Fatalf("confirm enrollment: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	codes, _ := body["recovery_codes"].([]any)
+	if len(codes) == 0 {
+		t.Fatalf("no recovery codes in %v", body)
+	}
+	return secret, codes
+}
+
+// mfaPending logs in with the password and returns the mfa-pending token.
+func (ts *testServer) mfaPending(t *testing.T, email, password string) string {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodPost, "/v1/tokens/authentication", "", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	if status != http.StatusAccepted {
+		t.Fatalf("login as %s: got status %d; want %d: %v", email, status, http.StatusAccepted, body)
+	}
+	return tokenIn(t, body, "mfa_token")
+}
+
+func TestMFALogin(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	secret, recoveryCodes := ts.enrollMFA(t, ts.login(t, "alice@example.com", "pa55word-alice"), "pa55word-alice")
+
+	// The code of the next step is accepted as clock drift. The confirmation used the
+	// current one, which can't be used again.
+	code := totpCode(t, secret, time.Now().Add(30*time.Second))
+	tests := []struct {
+		name  string
+		input map[string]string
+		want  int
+	}{
+		{"code", map[string]string{"code": code}, http.StatusCreated},
+		{"replayed code", map[string]string{"code": code}, http.StatusUnauthorized},
+		{"recovery code", map[string]string{"recovery_code": recoveryCodes[0].(string)}, http.StatusCreated},
+		{"used recovery code", map[string]string{"recovery_code": recoveryCodes[0].(string)}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		tt.input["mfa_token"] = ts.mfaPending(t, "alice@example.com", "pa55word-alice")
+		status, body := ts.do(t, http.MethodPost, "/v1/tokens/mfa", "", tt.input)
+		if status != tt.want {
+			t.Fatalf("%s: got status %d; want %d: %v", tt.name, status, tt.want, body)
+		}
+		if status == http.StatusCreated {
+			status, _ = ts.do(t, http.MethodGet, "/v1/me", tokenIn(t, body, "authentication_token"), nil)
+			if status != http.StatusOK {
+				t.Fatalf("%s: GET /v1/me: got status %d; want %d", tt.name, status, http.StatusOK)
+			}
+		}
+	}
+}
+
+// A pending token issued before MFA was disabled doesn't log in with the secret of an
+// enrollment which hasn't been confirmed.
+func TestMFALoginUnconfirmedEnrollment(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	token := ts.login(t, "alice@example.com", "pa55word-alice")
+	_, recoveryCodes := ts.enrollMFA(t, token, "pa55word-alice")
+	mfaToken := ts.mfaPending(t, "alice@example.com", "pa55word-alice")
+
+	status, body := ts.do(t, http.MethodDelete, "/v1/me/mfa", token, map[string]string{
+		"current_password": "pa55word-alice",
+		"recovery_code":    recoveryCodes[0].(string),
+	})
+	if status != http.StatusOK {
+		t.Fatalf("disable: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	status, body = ts.do(t, http.MethodPost, "/v1/me/mfa", token, map[string]string{"current_password": "pa55word-alice"})
+	if status != http.StatusCreated {
+		t.Fatalf("start enrollment: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	secret, _ := body["mfa"].(map[string]any)["secret"].(string)
+
+	status, _ = ts.do(t, http.MethodPost, "/v1/tokens/mfa", "", map[string]string{
+		"mfa_token": mfaToken,
+		"code":      totpCode(t, secret, time.Now()),
+	})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("got status %d; want %d", status, http.StatusUnauthorized)
+	}
+}
+
+// Users holding a role listed in Mfa.RequiredRoles get no permission until they
+// enroll, but can still reach /v1/me to do so.
+func TestMFARequiredRoles(t *testing.T) {
+	ts := newTestServer(t)
+	ts.app.config.Mfa.RequiredRoles = []string{"admin"}
+	admin := ts.loginAsAdmin(t)
+
+	status, body := ts.do(t, http.MethodGet, "/v1/user-infos", admin, nil)
+	if status != http.StatusForbidden || body["error"] != "your user account must enable two-factor authentication to access this resource" {
+		t.Fatalf("before enrolling: got status %d, %v; want %d", status, body, http.StatusForbidden)
+	}
+	status, _ = ts.do(t, http.MethodGet, "/v1/me", admin, nil)
+	if status != http.StatusOK {
+		t.Fatalf("GET /v1/me before enrolling: got status %d; want %d", status, http.StatusOK)
+	}
+
+	ts.enrollMFA(t, admin, "pa55word-admin")
+	status, _ = ts.do(t, http.MethodGet, "/v1/user-infos", admin, nil)
+	if status != http.StatusOK {
+		t.Fatalf("after enrolling: got status %d; want %d", status, http.StatusOK)
+	}
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/bxiit/greenlight/internal/data"
 	"github.com/bxiit/greenlight/internal/validator"
 	"golang.org/x/time/rate"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 func (app *application) recoverPanic(next http.Handler) http.Handler {
@@ -35,33 +41,202 @@ func (app *application) recoverPanic(next http.Handler) http.Handler {
 	})
 }
 
-func (app *application) rateLimit(next http.Handler) http.Handler {
-	// Initialize a new rate Limiter which allows an average of 2 requests per second,
-	// with a maximum of 4 requests in a single ‘Burst’.
-	limiter := rate.NewLimiter(10, 12)
-	// The function we are returning is a closure, which 'closes over' the Limiter
-	// variable.
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Call Limiter.Allow() to see if the request is permitted, and if it's not,
-		// then we call the rateLimitExceededResponse() helper to return a 429 Too Many
-		// Requests response (we will create this helper in a minute).
-		if !limiter.Allow() {
-			app.rateLimitExceededResponse(w, r)
+// rateLimitClient holds the token bucket of one client and when it was last used.
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientLimiters keeps a token bucket per client, keyed by either the authenticated
+// user info ID or the client IP address.
+type clientLimiters struct {
+	mu      sync.Mutex
+	clients map[string]*rateLimitClient
+}
+
+func newClientLimiters() *clientLimiters {
+	return &clientLimiters{clients: make(map[string]*rateLimitClient)}
+}
+
+// allow takes a token from the bucket of the key, creating the bucket if needed. It
+// reports whether a token was available and how many are left.
+func (l *clientLimiters) allow(key string, rps float64, burst int) (bool, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, found := l.clients[key]
+	if !found {
+		c = &rateLimitClient{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = time.Now()
+
+	allowed := c.limiter.AllowN(c.lastSeen, 1)
+	return allowed, c.limiter.TokensAt(c.lastSeen)
+}
+
+// evictIdle deletes the buckets of clients which haven't been seen within idle.
+func (l *clientLimiters) evictIdle(idle time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, client := range l.clients {
+		if time.Since(client.lastSeen) > idle {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// evictRateLimitClients removes the buckets of clients which haven't been seen within
+// the last three minutes once every minute. It returns once ctx is cancelled.
+func (app *application) evictRateLimitClients(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-time.After(time.Minute):
 		}
-		next.ServeHTTP(w, r)
+		app.rateLimiters.evictIdle(3 * time.Minute)
+	}
+}
+
+// rateLimitIP limits every request per client IP address. It runs before
+// authenticate, so that requests with a bad token are limited before they cost a
+// database query.
+func (app *application) rateLimitIP(next http.Handler) http.Handler {
+	return app.rateLimit(next, func(r *http.Request) string {
+		return "ip:" + app.clientIP(r)
 	})
 }
 
-func (app *application) requireAdminRole(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// rateLimitUser also limits authenticated requests per user info, regardless of the
+// address they come from. Anonymous requests pass straight through.
+func (app *application) rateLimitUser(next http.Handler) http.Handler {
+	return app.rateLimit(next, func(r *http.Request) string {
 		userInfo := app.contextGetUserInfo(r)
-		if userInfo.Role != "admin" {
-			app.notPermittedResponse(w, r)
+		if userInfo.IsAnonymous() {
+			return ""
+		}
+		return fmt.Sprintf("user_info:%d", userInfo.ID)
+	})
+}
+
+// rateLimit takes a token from the bucket of the client named by clientKey, or lets
+// the request through if clientKey returns "".
+func (app *application) rateLimit(next http.Handler, clientKey func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only carry out the check if rate limiting is enabled.
+		if !app.config.Limiter.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := clientKey(r)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, tokens := app.rateLimiters.allow(key, app.config.Limiter.Rps, app.config.Limiter.Burst)
+
+		setRateLimitHeaders(w, app.config.Limiter.Rps, app.config.Limiter.Burst, tokens)
+		// If the request isn't permitted, call the rateLimitExceededResponse() helper
+		// to return a 429 Too Many Requests response telling the client when the next
+		// token is available.
+		if !allowed {
+			app.rateLimitExceededResponse(w, r, secondsUntil(1-tokens, app.config.Limiter.Rps))
 			return
 		}
-		next(w, r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setRateLimitHeaders adds the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers describing the state of a client's token bucket. Reset is
+// the number of seconds until the bucket is full again.
+func setRateLimitHeaders(w http.ResponseWriter, rps float64, burst int, tokens float64) {
+	remaining := int(math.Floor(tokens))
+	if remaining < 0 {
+		remaining = 0
 	}
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(burst))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(secondsUntil(float64(burst)-tokens, rps)))
+}
+
+// secondsUntil returns how many whole seconds it takes to refill the given number of
+// tokens at rps tokens per second.
+func secondsUntil(tokens, rps float64) int {
+	if tokens <= 0 || rps <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / rps))
+}
+
+// clientIP returns the address of the client that made the request. The
+// X-Forwarded-For header is only honoured when the request comes from one of the
+// configured trusted proxies, in which case the header is walked from right to left
+// and the first address which isn't itself a trusted proxy is used.
+func (app *application) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !app.isTrustedProxy(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !app.isTrustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (app *application) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range app.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies converts the Limiter.TrustedProxies config entries, which may
+// be either plain IP addresses or CIDR ranges, into a slice of networks.
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
 }
 
 func (app *application) authenticate(next http.Handler) http.Handler {
@@ -83,17 +258,35 @@ func (app *application) authenticate(next http.Handler) http.Handler {
 			return
 		}
 		// Otherwise, we expect the value of the Authorization header to be in the format
-		// "Bearer <token>". We try to split this into its constituent parts, and if the
-		// header isn't in the expected format we return a 401 Unauthorized response
-		// using the invalidAuthenticationTokenResponse() helper (which we will create
-		// in a moment).
-		headerParts := strings.Split(authorizationHeader, " ")
-		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		// "Bearer <token>". If the header isn't in the expected format we return a 401
+		// Unauthorized response using the invalidAuthenticationTokenResponse() helper.
+		token, ok := bearerToken(r)
+		if !ok {
 			app.invalidAuthenticationTokenResponse(w, r)
 			return
 		}
-		// Extract the actual authentication token from the header parts.
-		token := headerParts[1]
+		// In jwt mode the token is verified against the keyset and the user info
+		// and its permissions are taken from the claims, without a database query.
+		if app.jwtKeys != nil {
+			claims, err := app.jwtKeys.Parse(token)
+			if err != nil {
+				app.invalidAuthenticationTokenResponse(w, r)
+				return
+			}
+			userID, err := claims.UserID()
+			if err != nil {
+				app.invalidAuthenticationTokenResponse(w, r)
+				return
+			}
+			r = app.contextSetUserInfo(r, &data.UserInfo{
+				ID:        userID,
+				Role:      claims.Role,
+				Activated: claims.Activated,
+			})
+			r = app.contextSetPermissions(r, claims.Permissions)
+			next.ServeHTTP(w, r)
+			return
+		}
 		// Validate the token to make sure it is in a sensible format.
 		v := validator.New()
 		// If the token isn't valid, use the invalidAuthenticationTokenResponse()
@@ -120,13 +313,20 @@ func (app *application) authenticate(next http.Handler) http.Handler {
 		// Call the contextSetUser() helper to add the user information to the request
 		// context.
 		r = app.contextSetUserInfo(r, userInfo)
-		println("authenticate")
-		println(userInfo.Role)
 		// Call the next handler in the chain.
 		next.ServeHTTP(w, r)
 	})
 }
 
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	headerParts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return "", false
+	}
+	return headerParts[1], true
+}
+
 func (app *application) requireActivatedUser(next http.HandlerFunc) http.HandlerFunc {
 	// Rather than returning this http.HandlerFunc we assign it to the variable fn.
 	fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -185,11 +385,24 @@ func (app *application) requirePermission(code string, next http.HandlerFunc) ht
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		// Retrieve the user from the request context.
 		userInfo := app.contextGetUserInfo(r)
-		// Get the slice of permissions for the user.
-		permissions, err := app.models.Permissions.GetAllForUserInfo(userInfo.ID)
-		if err != nil {
-			app.serverErrorResponse(w, r, err)
-			return
+		// Get the slice of permissions for the user, unless they came with a signed
+		// access token.
+		permissions, ok := app.contextGetPermissions(r)
+		if !ok {
+			missing, err := app.mfaMissing(userInfo.ID)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			if missing {
+				app.mfaRequiredResponse(w, r)
+				return
+			}
+			permissions, err = app.models.Permissions.GetAllForUserInfo(userInfo.ID)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
 		}
 		// Check if the slice includes the required permission. If it doesn't, then
 		// return a 403 Forbidden response.
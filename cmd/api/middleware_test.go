@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Requests with a token that doesn't match any user info are limited per IP address
+// like anonymous ones, rather than each costing a token lookup.
+func TestRateLimitIPBeforeAuthenticate(t *testing.T) {
+	ts := newTestServer(t)
+	ts.app.config.Limiter.Enabled = true
+	ts.app.config.Limiter.Rps = 0.01
+	ts.app.config.Limiter.Burst = 2
+
+	token := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	for i := 0; i < 2; i++ {
+		status, _ := ts.do(t, http.MethodGet, "/v1/me", token, nil)
+		if status != http.StatusUnauthorized {
+			t.Fatalf("request %d: got status %d; want %d", i+1, status, http.StatusUnauthorized)
+		}
+	}
+	status, _ := ts.do(t, http.MethodGet, "/v1/me", token, nil)
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("got status %d; want %d", status, http.StatusTooManyRequests)
+	}
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
-	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	pb "github.com/bxiit/greenlight/rpc"
 	"net/http"
 	"time"
 )
@@ -12,8 +13,9 @@ import (
 type MIHandler interface {
 	CreateModuleInfoHandler(w http.ResponseWriter, r *http.Request)
 	GetModuleInfoHandler(w http.ResponseWriter, r *http.Request)
-	GetLatestFiftyModuleInfosHandler(w http.ResponseWriter, r *http.Request)
+	ListModuleInfosHandler(w http.ResponseWriter, r *http.Request)
 	EditModuleInfoHandler(w http.ResponseWriter, r *http.Request)
+	PatchModuleInfoHandler(w http.ResponseWriter, r *http.Request)
 	DeleteModuleInfoHandler(w http.ResponseWriter, r *http.Request)
 }
 
@@ -61,6 +63,7 @@ func (mi *ModuleInfoHandler) GetModuleInfoHandler(w http.ResponseWriter, r *http
 	id, err := ReadIDParam(r)
 	if err != nil {
 		NotFoundResponse(w, r)
+		return
 	}
 
 	//moduleInfo, err := App.models.ModuleInfos.Get(id)
@@ -75,32 +78,61 @@ func (mi *ModuleInfoHandler) GetModuleInfoHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	err = WriteJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, nil)
+	// The ETag lets clients send the version they read back in an If-Match header
+	// when they update the record.
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = WriteJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		ServerErrorResponse(w, r, err)
 	}
 }
 
-func (mi *ModuleInfoHandler) GetLatestFiftyModuleInfosHandler(w http.ResponseWriter, r *http.Request) {
-	moduleInfos, err := mi.Repo.GetLatestFifty()
+func (mi *ModuleInfoHandler) ListModuleInfosHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ModuleName  string
+		ExamType    string
+		MinDuration int
+		MaxDuration int
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
 
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			NotFoundResponse(w, r)
-		}
+	input.ModuleName = ReadString(qs, "module_name", "")
+	input.ExamType = ReadString(qs, "exam_type", "")
+	input.MinDuration = ReadInt(qs, "min_duration", 0, v)
+	input.MaxDuration = ReadInt(qs, "max_duration", 0, v)
+
+	input.Filters.Page = ReadInt(qs, "page", 1, v)
+	input.Filters.PageSize = ReadInt(qs, "page_size", 20, v)
+	input.Filters.Sort = ReadString(qs, "sort", "-id")
+	input.Filters.SortSafelist = data.ModuleInfoSortSafelist
+
+	if data.ValidateModuleInfoFilters(v, input.MinDuration, input.MaxDuration, input.Filters); !v.Valid() {
+		FailedValidationResponse(w, r, v.Errors)
+		return
 	}
 
-	err = WriteJSON(w, http.StatusOK, Envelope{"module_infos": moduleInfos}, nil)
+	moduleInfos, metadata, err := mi.Repo.GetAll(input.ModuleName, input.ExamType, input.MinDuration, input.MaxDuration, input.Filters)
 	if err != nil {
+		ServerErrorResponse(w, r, err)
 		return
 	}
+
+	err = WriteJSON(w, http.StatusOK, Envelope{"module_infos": moduleInfos, "metadata": metadata}, nil)
+	if err != nil {
+		ServerErrorResponse(w, r, err)
+	}
 }
 
 func (mi *ModuleInfoHandler) EditModuleInfoHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := ReadIDParam(r)
 	if err != nil {
 		NotFoundResponse(w, r)
+		return
 	}
 
 	moduleInfo, err := mi.Repo.Get(id)
@@ -114,6 +146,13 @@ func (mi *ModuleInfoHandler) EditModuleInfoHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// Refuse the update straight away if the client read an older version of the
+	// record than the one currently stored.
+	if !ifMatchSatisfied(r, versionETag(moduleInfo.Version)) {
+		PreconditionFailedResponse(w, r)
+		return
+	}
+
 	var input struct {
 		ModuleName     string        `json:"moduleName"`
 		ModuleDuration time.Duration `json:"moduleDuration"`
@@ -131,12 +170,97 @@ func (mi *ModuleInfoHandler) EditModuleInfoHandler(w http.ResponseWriter, r *htt
 	moduleInfo.ExamType = input.ExamType
 
 	err = mi.Repo.Update(moduleInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			EditConflictResponse(w, r)
+		default:
+			ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = WriteJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		ServerErrorResponse(w, r, err)
+	}
+}
+
+// PatchModuleInfoHandler applies a partial update: only the fields present in the
+// request body are validated and changed, the rest of the record is left as it is.
+func (mi *ModuleInfoHandler) PatchModuleInfoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := ReadIDParam(r)
+	if err != nil {
+		NotFoundResponse(w, r)
 		return
 	}
 
-	err = WriteJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, nil)
+	moduleInfo, err := mi.Repo.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			NotFoundResponse(w, r)
+		default:
+			ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !ifMatchSatisfied(r, versionETag(moduleInfo.Version)) {
+		PreconditionFailedResponse(w, r)
+		return
+	}
+
+	// Use pointers so that a field missing from the JSON body (nil) can be told
+	// apart from a field explicitly set to its zero value.
+	var input struct {
+		ModuleName     *string        `json:"moduleName"`
+		ModuleDuration *time.Duration `json:"moduleDuration"`
+		ExamType       *string        `json:"examType"`
+	}
+
+	err = ReadJSON(w, r, &input)
+	if err != nil {
+		BadRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if input.ModuleName != nil {
+		data.ValidateModuleName(v, *input.ModuleName)
+		moduleInfo.ModuleName = *input.ModuleName
+	}
+	if input.ModuleDuration != nil {
+		data.ValidateModuleDuration(v, *input.ModuleDuration)
+		moduleInfo.ModuleDuration = *input.ModuleDuration
+	}
+	if input.ExamType != nil {
+		data.ValidateExamType(v, *input.ExamType)
+		moduleInfo.ExamType = *input.ExamType
+	}
+	if !v.Valid() {
+		FailedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = mi.Repo.Update(moduleInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			EditConflictResponse(w, r)
+		default:
+			ServerErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = WriteJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		ServerErrorResponse(w, r, err)
 	}
@@ -189,6 +313,7 @@ func (app *application) CreateModuleInfoHandler(w http.ResponseWriter, r *http.R
 		app.serverErrorResponse(w, r, err)
 		return
 	}
+	app.moduleInfoChanges.publish(pb.ChangeKind_CHANGE_KIND_CREATED, moduleInfo)
 
 	//App.gormDB.Table("module_info").Create(&moduleInfo)
 	headers := make(http.Header)
@@ -204,6 +329,7 @@ func (app *application) GetModuleInfoHandler(w http.ResponseWriter, r *http.Requ
 	id, err := app.readIDParam(r)
 	if err != nil {
 		app.notFoundResponse(w, r)
+		return
 	}
 
 	moduleInfo, err := app.models.ModuleInfos.Get(id)
@@ -217,32 +343,61 @@ func (app *application) GetModuleInfoHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, nil)
+	// The ETag lets clients send the version they read back in an If-Match header
+	// when they update the record.
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
-func (app *application) GetLatestFiftyModuleInfosHandler(w http.ResponseWriter, r *http.Request) {
-	moduleInfos, err := app.models.ModuleInfos.GetLatestFifty()
+func (app *application) ListModuleInfosHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ModuleName  string
+		ExamType    string
+		MinDuration int
+		MaxDuration int
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
 
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			app.notFoundResponse(w, r)
-		}
+	input.ModuleName = app.readString(qs, "module_name", "")
+	input.ExamType = app.readString(qs, "exam_type", "")
+	input.MinDuration = app.readInt(qs, "min_duration", 0, v)
+	input.MaxDuration = app.readInt(qs, "max_duration", 0, v)
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Filters.Sort = app.readString(qs, "sort", "-id")
+	input.Filters.SortSafelist = data.ModuleInfoSortSafelist
+
+	if data.ValidateModuleInfoFilters(v, input.MinDuration, input.MaxDuration, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, Envelope{"module_infos": moduleInfos}, nil)
+	moduleInfos, metadata, err := app.models.ModuleInfos.GetAll(input.ModuleName, input.ExamType, input.MinDuration, input.MaxDuration, input.Filters)
 	if err != nil {
+		app.serverErrorResponse(w, r, err)
 		return
 	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"module_infos": moduleInfos, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
 }
 
 func (app *application) EditModuleInfoHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := app.readIDParam(r)
 	if err != nil {
 		app.notFoundResponse(w, r)
+		return
 	}
 
 	moduleInfo, err := app.models.ModuleInfos.Get(id)
@@ -256,6 +411,13 @@ func (app *application) EditModuleInfoHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Refuse the update straight away if the client read an older version of the
+	// record than the one currently stored.
+	if !ifMatchSatisfied(r, versionETag(moduleInfo.Version)) {
+		app.preconditionFailedResponse(w, r)
+		return
+	}
+
 	var input struct {
 		ModuleName     string        `json:"moduleName"`
 		ModuleDuration time.Duration `json:"moduleDuration"`
@@ -273,12 +435,99 @@ func (app *application) EditModuleInfoHandler(w http.ResponseWriter, r *http.Req
 	moduleInfo.ExamType = input.ExamType
 
 	err = app.models.ModuleInfos.Update(moduleInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	app.moduleInfoChanges.publish(pb.ChangeKind_CHANGE_KIND_UPDATED, moduleInfo)
+
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// PatchModuleInfoHandler applies a partial update: only the fields present in the
+// request body are validated and changed, the rest of the record is left as it is.
+func (app *application) PatchModuleInfoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, nil)
+	moduleInfo, err := app.models.ModuleInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !ifMatchSatisfied(r, versionETag(moduleInfo.Version)) {
+		app.preconditionFailedResponse(w, r)
+		return
+	}
+
+	// Use pointers so that a field missing from the JSON body (nil) can be told
+	// apart from a field explicitly set to its zero value.
+	var input struct {
+		ModuleName     *string        `json:"moduleName"`
+		ModuleDuration *time.Duration `json:"moduleDuration"`
+		ExamType       *string        `json:"examType"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if input.ModuleName != nil {
+		data.ValidateModuleName(v, *input.ModuleName)
+		moduleInfo.ModuleName = *input.ModuleName
+	}
+	if input.ModuleDuration != nil {
+		data.ValidateModuleDuration(v, *input.ModuleDuration)
+		moduleInfo.ModuleDuration = *input.ModuleDuration
+	}
+	if input.ExamType != nil {
+		data.ValidateExamType(v, *input.ExamType)
+		moduleInfo.ExamType = *input.ExamType
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.ModuleInfos.Update(moduleInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	app.moduleInfoChanges.publish(pb.ChangeKind_CHANGE_KIND_UPDATED, moduleInfo)
+
+	headers := make(http.Header)
+	headers.Set("ETag", versionETag(moduleInfo.Version))
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"module_info": moduleInfo}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -300,6 +549,7 @@ func (app *application) DeleteModuleInfoHandler(w http.ResponseWriter, r *http.R
 		}
 		return
 	}
+	app.moduleInfoChanges.publish(pb.ChangeKind_CHANGE_KIND_DELETED, &data.ModuleInfo{ID: int(id)})
 
 	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "module info successfully deleted"}, nil)
 	if err != nil {
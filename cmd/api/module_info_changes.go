@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	pb "github.com/bxiit/greenlight/rpc"
+	"sync"
+)
+
+// moduleInfoFeedBuffer is how many changes a WatchModuleInfos stream may lag behind
+// before it is dropped.
+const moduleInfoFeedBuffer = 64
+
+// moduleInfoFeed fans the module info changes made through this process out to the
+// WatchModuleInfos streams. Publishing never blocks: a subscriber whose buffer is full
+// is dropped instead of holding up the handler which made the change.
+type moduleInfoFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan *pb.ModuleInfoChange]struct{}
+	closed      bool
+}
+
+func newModuleInfoFeed() *moduleInfoFeed {
+	return &moduleInfoFeed{subscribers: make(map[chan *pb.ModuleInfoChange]struct{})}
+}
+
+// subscribe returns a channel receiving every change published from now on, and a
+// function which has to be called once the caller stops reading. The channel is
+// closed if the subscriber falls behind or the feed is closed.
+func (f *moduleInfoFeed) subscribe() (<-chan *pb.ModuleInfoChange, func()) {
+	ch := make(chan *pb.ModuleInfoChange, moduleInfoFeedBuffer)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	f.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subscribers[ch]; ok {
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publish is a no-op on a nil feed, so that handlers work in an application which
+// doesn't serve gRPC.
+func (f *moduleInfoFeed) publish(kind pb.ChangeKind, moduleInfo *data.ModuleInfo) {
+	if f == nil {
+		return
+	}
+	change := &pb.ModuleInfoChange{Kind: kind, ModuleInfo: moduleInfoToProto(moduleInfo)}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subscribers {
+		select {
+		case ch <- change:
+		default:
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends every subscription and refuses new ones. It is called on shutdown, since
+// GracefulStop() would otherwise wait for the streams until the deadline.
+func (f *moduleInfoFeed) close() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for ch := range f.subscribers {
+		delete(f.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (f *moduleInfoFeed) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
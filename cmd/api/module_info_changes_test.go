@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	pb "github.com/bxiit/greenlight/rpc"
+	"testing"
+)
+
+// A subscriber which doesn't keep up is dropped without blocking publish(), while
+// the others keep receiving changes until the feed is closed.
+func TestModuleInfoFeed(t *testing.T) {
+	feed := newModuleInfoFeed()
+	slow, _ := feed.subscribe()
+	fast, unsubscribe := feed.subscribe()
+	defer unsubscribe()
+
+	for i := 1; i <= moduleInfoFeedBuffer+1; i++ {
+		feed.publish(pb.ChangeKind_CHANGE_KIND_CREATED, &data.ModuleInfo{ID: i})
+		if change := <-fast; change.ModuleInfo.Id != int64(i) {
+			t.Fatalf("got module info %d; want %d", change.ModuleInfo.Id, i)
+		}
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != moduleInfoFeedBuffer {
+		t.Errorf("slow subscriber got %d changes; want %d", received, moduleInfoFeedBuffer)
+	}
+	if feed.isClosed() {
+		t.Error("feed closed after dropping a subscriber")
+	}
+
+	feed.close()
+	if _, ok := <-fast; ok {
+		t.Error("subscription still open after close()")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	pb "github.com/bxiit/greenlight/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// ModuleInfoService serves rpc.ModuleInfoService over a ModuleInfoRepository, the
+// way ModuleInfoHandler serves the HTTP endpoints.
+type ModuleInfoService struct {
+	pb.UnimplementedModuleInfoServiceServer
+	Repo    data.ModuleInfoRepository
+	Changes *moduleInfoFeed
+	app     *application
+}
+
+func (s *ModuleInfoService) CreateModuleInfo(ctx context.Context, req *pb.CreateModuleInfoRequest) (*pb.ModuleInfo, error) {
+	moduleInfo := &data.ModuleInfo{
+		ModuleName:     req.ModuleName,
+		ModuleDuration: time.Duration(req.ModuleDuration),
+		ExamType:       req.ExamType,
+	}
+
+	v := validator.New()
+	if data.ValidateModuleInfo(v, moduleInfo); !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	err := s.Repo.Create(moduleInfo)
+	if err != nil {
+		return nil, s.app.serverErrorStatus(ctx, err)
+	}
+	// Create() doesn't read updated_at back, but it is set to the same now().
+	moduleInfo.UpdatedAt = moduleInfo.CreatedAt
+
+	s.Changes.publish(pb.ChangeKind_CHANGE_KIND_CREATED, moduleInfo)
+	return moduleInfoToProto(moduleInfo), nil
+}
+
+func (s *ModuleInfoService) GetModuleInfo(ctx context.Context, req *pb.GetModuleInfoRequest) (*pb.ModuleInfo, error) {
+	moduleInfo, err := s.Repo.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return moduleInfoToProto(moduleInfo), nil
+}
+
+func (s *ModuleInfoService) ListModuleInfos(ctx context.Context, req *pb.ListModuleInfosRequest) (*pb.ListModuleInfosResponse, error) {
+	filters := data.Filters{
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		Sort:         req.Sort,
+		SortSafelist: data.ModuleInfoSortSafelist,
+	}
+	// Zero values mean the field wasn't set; use the same defaults as the query
+	// string parameters of GET /v1/module-infos.
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = "-id"
+	}
+
+	v := validator.New()
+	if data.ValidateModuleInfoFilters(v, int(req.MinDuration), int(req.MaxDuration), filters); !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	moduleInfos, metadata, err := s.Repo.GetAll(req.ModuleName, req.ExamType, int(req.MinDuration), int(req.MaxDuration), filters)
+	if err != nil {
+		return nil, s.app.serverErrorStatus(ctx, err)
+	}
+
+	resp := &pb.ListModuleInfosResponse{
+		ModuleInfos: make([]*pb.ModuleInfo, 0, len(moduleInfos)),
+		Metadata:    metadataToProto(metadata),
+	}
+	for _, moduleInfo := range moduleInfos {
+		resp.ModuleInfos = append(resp.ModuleInfos, moduleInfoToProto(moduleInfo))
+	}
+	return resp, nil
+}
+
+// UpdateModuleInfo works like PatchModuleInfoHandler, with the version field in place
+// of the If-Match header.
+func (s *ModuleInfoService) UpdateModuleInfo(ctx context.Context, req *pb.UpdateModuleInfoRequest) (*pb.ModuleInfo, error) {
+	moduleInfo, err := s.Repo.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	if req.Version != "" && req.Version != moduleInfo.Version {
+		return nil, preconditionFailedStatus()
+	}
+
+	v := validator.New()
+	if req.ModuleName != nil {
+		data.ValidateModuleName(v, *req.ModuleName)
+		moduleInfo.ModuleName = *req.ModuleName
+	}
+	if req.ModuleDuration != nil {
+		data.ValidateModuleDuration(v, time.Duration(*req.ModuleDuration))
+		moduleInfo.ModuleDuration = time.Duration(*req.ModuleDuration)
+	}
+	if req.ExamType != nil {
+		data.ValidateExamType(v, *req.ExamType)
+		moduleInfo.ExamType = *req.ExamType
+	}
+	if !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	err = s.Repo.Update(moduleInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			return nil, editConflictStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	s.Changes.publish(pb.ChangeKind_CHANGE_KIND_UPDATED, moduleInfo)
+	return moduleInfoToProto(moduleInfo), nil
+}
+
+func (s *ModuleInfoService) DeleteModuleInfo(ctx context.Context, req *pb.DeleteModuleInfoRequest) (*pb.DeleteModuleInfoResponse, error) {
+	err := s.Repo.Delete(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	s.Changes.publish(pb.ChangeKind_CHANGE_KIND_DELETED, &data.ModuleInfo{ID: int(req.Id)})
+	return &pb.DeleteModuleInfoResponse{}, nil
+}
+
+func (s *ModuleInfoService) WatchModuleInfos(req *pb.WatchModuleInfosRequest, stream pb.ModuleInfoService_WatchModuleInfosServer) error {
+	changes, unsubscribe := s.Changes.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				if s.Changes.isClosed() {
+					return status.Error(codes.Unavailable, "the server is shutting down")
+				}
+				return status.Error(codes.ResourceExhausted, "too many changes were not received in time")
+			}
+			err := stream.Send(change)
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+func moduleInfoToProto(moduleInfo *data.ModuleInfo) *pb.ModuleInfo {
+	m := &pb.ModuleInfo{
+		Id:             int64(moduleInfo.ID),
+		ModuleName:     moduleInfo.ModuleName,
+		ModuleDuration: int64(moduleInfo.ModuleDuration),
+		ExamType:       moduleInfo.ExamType,
+		Version:        moduleInfo.Version,
+	}
+	// A deleted module info only has its ID.
+	if !moduleInfo.CreatedAt.IsZero() {
+		m.CreatedAt = timestamppb.New(moduleInfo.CreatedAt)
+	}
+	if !moduleInfo.UpdatedAt.IsZero() {
+		m.UpdatedAt = timestamppb.New(moduleInfo.UpdatedAt)
+	}
+	return m
+}
@@ -1,117 +1,70 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"github.com/bxiit/greenlight/internal/data"
-	"github.com/bxiit/greenlight/internal/jsonlog"
-	"github.com/bxiit/greenlight/internal/mailer"
-	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
-	"io"
 	"net/http"
-	"os"
-	"strings"
+	"strconv"
 	"testing"
 )
 
 type InfosTestSuite struct {
 	suite.Suite
-	Server *http.Server
-	Client *http.Client
-	App    *application
+	Server *testServer
+	Module *data.ModuleInfo
 }
 
 func TestInfosTestSuite(t *testing.T) {
 	suite.Run(t, &InfosTestSuite{})
 }
 
-func (s *InfosTestSuite) SetupSuite() {
-	var cfg Config
-	viper.SetConfigFile("./config.json")
-	err := viper.ReadInConfig()
-	s.Nil(err)
-
-	err = viper.Unmarshal(&cfg)
-	s.Nil(err)
-
-	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
+func (s *InfosTestSuite) SetupTest() {
+	s.Server = newTestServer(s.T())
 
-	db, err := openDB(cfg)
-	if err != nil {
-		logger.PrintFatal(err, nil)
-	}
-	defer db.Close()
-
-	app := &application{
-		config: cfg,
-		logger: logger,
-		models: data.NewModels(db), // data.NewModels() function to initialize App Models struct
-		mailer: mailer.New(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.Smtp.Sender),
-	}
-
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: app.routes(),
+	s.Module = &data.ModuleInfo{
+		ModuleName:     "Test1",
+		ModuleDuration: 10,
+		ExamType:       "Final",
 	}
-
-	go func() {
-		err := server.ListenAndServe()
-		if err != nil {
-			_ = fmt.Errorf("failed to listen")
-		}
-	}()
-	s.Server = server
-	s.Client = &http.Client{}
-	s.App = app
+	err := s.Server.app.models.ModuleInfos.Create(s.Module)
+	s.Require().Nil(err)
 }
 
 func (s *InfosTestSuite) TestModuleInfoModel_Insert() {
-	// Create a new ModuleInfo instance with the desired values
-	moduleInfo := &data.ModuleInfo{
-		ModuleName:     "Test Module",
-		ModuleDuration: 60000000000,
-		ExamType:       "Final",
-	}
-
-	moduleInfoJson, err := json.Marshal(moduleInfo)
-	s.Nil(err)
-	request, err := http.NewRequest(http.MethodPost, "http://localhost:4002/v1/module-infos", strings.NewReader(string(moduleInfoJson)))
-	s.Nil(err)
-
-	request.Header.Add("Content-Type", "application/json")
-	response, err := s.Client.Do(request)
-	s.Nil(err)
-	s.Equal(403, response.StatusCode)
-
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	s.Nil(err)
-
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	s.Nil(err)
+	status, result := s.Server.do(s.T(), http.MethodPost, "/v1/module-infos", "", map[string]any{
+		"moduleName":     "Test Module",
+		"moduleDuration": 10,
+		"examType":       "Final",
+	})
+	s.Equal(http.StatusUnauthorized, status)
 
 	message, exists := result["error"]
 	s.True(exists, "Expected 'error' key in response")
-	s.Equal("your user account doesn't have the necessary permissions to access this resource", message)
+	s.Equal("you must be authenticated to access this resource", message)
 }
 
-func (s *InfosTestSuite) TestModuleInfoModel_Get() {
-	request, err := http.NewRequest(http.MethodGet, "http://localhost:4002/v1/module-infos/6", nil)
-	s.Nil(err)
-	request.Header.Add("Content-Type", "application/json")
+func (s *InfosTestSuite) TestModuleInfoModel_Insert_Authenticated() {
+	token := s.Server.loginAsAdmin(s.T())
 
-	response, err := s.Client.Do(request)
-	s.Nil(err)
+	status, result := s.Server.do(s.T(), http.MethodPost, "/v1/module-infos", token, map[string]any{
+		"moduleName":     "Test Module",
+		"moduleDuration": 10,
+		"examType":       "Final",
+	})
+	s.Equal(http.StatusCreated, status)
 
-	body, err := io.ReadAll(response.Body)
-	s.Nil(err)
+	miMap, ok := result["module_info"].(map[string]any)
+	s.Require().True(ok, "Module info expected")
+	s.Equal("Test Module", miMap["moduleName"])
 
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
+	moduleInfo, err := s.Server.app.models.ModuleInfos.Get(int64(miMap["id"].(float64)))
 	s.Nil(err)
+	s.Equal("Final", moduleInfo.ExamType)
+}
+
+func (s *InfosTestSuite) TestModuleInfoModel_Get() {
+	status, result := s.Server.do(s.T(), http.MethodGet, "/v1/module-infos/"+strconv.Itoa(s.Module.ID), "", nil)
+	s.Equal(http.StatusUnauthorized, status)
 
 	message, exists := result["error"]
 	s.True(exists, "Expected 'error' key in response")
@@ -119,22 +72,11 @@ func (s *InfosTestSuite) TestModuleInfoModel_Get() {
 }
 
 func (s *InfosTestSuite) TestModuleInfoModel_Get_Authenticated() {
-	token := AuthenticateUserInfo("admin@example.com", "adminpassword")
+	token := s.Server.loginAsAdmin(s.T())
 	s.NotEmpty(token, "Token is empty")
 
-	request, err := http.NewRequest(http.MethodGet, "http://localhost:4002/v1/module-infos/6", nil)
-	s.Nil(err)
-	request.Header.Add("Authorization", "Bearer "+token)
-
-	response, err := s.Client.Do(request)
-	s.Nil(err)
-
-	body, err := io.ReadAll(response.Body)
-	s.Nil(err)
-
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	s.Nil(err)
+	status, result := s.Server.do(s.T(), http.MethodGet, "/v1/module-infos/"+strconv.Itoa(s.Module.ID), token, nil)
+	s.Equal(http.StatusOK, status)
 
 	message, exists := result["error"]
 	s.False(exists, "'error' message not expected")
@@ -152,47 +94,35 @@ func (s *InfosTestSuite) TestModuleInfoModel_Get_Authenticated() {
 	}
 
 	s.True(moduleInfoExists, "Module info expected")
-	s.Equal(6, mi.ID, "Id of module are not equal")
+	s.Equal(s.Module.ID, mi.ID, "Id of module are not equal")
 	s.Equal("Test1", mi.ModuleName)
 }
 
-func (s *InfosTestSuite) TestModuleInfoModel_GetLatestFifty() {
-	token := AuthenticateUserInfo("admin@example.com", "adminpassword")
+func (s *InfosTestSuite) TestModuleInfoModel_List() {
+	token := s.Server.loginAsAdmin(s.T())
 	s.NotEmpty(token, "Token is empty")
 
-	request, err := http.NewRequest(http.MethodGet, "http://localhost:4002/v1/module-infos", nil)
-	s.Nil(err)
-	request.Header.Add("Authorization", "Bearer "+token)
-	response, err := s.Client.Do(request)
-	s.Nil(err)
-	defer response.Body.Close()
-	s.Equal(200, response.StatusCode)
+	status, result := s.Server.do(s.T(), http.MethodGet, "/v1/module-infos", token, nil)
+	s.Equal(http.StatusOK, status)
 
-	// {"error":"you must be authenticated to access this resource"}
-	body, err := io.ReadAll(response.Body)
-	s.Nil(err)
-
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	s.Nil(err)
+	moduleInfos, ok := result["module_infos"].([]any)
+	s.True(ok, "Module infos expected")
+	s.Len(moduleInfos, 1)
 }
 
-func (s *InfosTestSuite) TestModuleInfoModel_GetLatestFifty_Authenticated() {
-	request, err := http.NewRequest(http.MethodGet, "http://localhost:4002/v1/module-infos", nil)
-	s.Nil(err)
-	response, err := s.Client.Do(request)
-	s.Nil(err)
-	defer response.Body.Close()
-	s.Equal(401, response.StatusCode)
-
-	body, err := io.ReadAll(response.Body)
-	s.Nil(err)
-
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	s.Nil(err)
+func (s *InfosTestSuite) TestModuleInfoModel_List_Authenticated() {
+	status, result := s.Server.do(s.T(), http.MethodGet, "/v1/module-infos", "", nil)
+	s.Equal(http.StatusUnauthorized, status)
 
 	message, exists := result["error"]
 	s.True(exists, "Expected 'error' key in response")
 	s.Equal("you must be authenticated to access this resource", message)
 }
+
+func (s *InfosTestSuite) TestModuleInfoModel_List_NotPermitted() {
+	s.Server.createUserInfo(s.T(), "alice@example.com", "pa55word-alice")
+	token := s.Server.login(s.T(), "alice@example.com", "pa55word-alice")
+
+	status, _ := s.Server.do(s.T(), http.MethodGet, "/v1/module-infos", token, nil)
+	s.Equal(http.StatusForbidden, status)
+}
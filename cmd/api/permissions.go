@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+// ListPermissionsHandler returns every permission code known to the system.
+func (app *application) ListPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	permissions, err := app.models.Permissions.ListAll()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"permissions": permissions}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreatePermissionHandler adds a new permission code which can then be granted to
+// user infos or added to roles.
+func (app *application) CreatePermissionHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Code string `json:"code"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if data.ValidatePermissionCode(v, input.Code); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	// The code and its audit entry are stored in one transaction.
+	err = app.models.Permissions.InsertAudited(app.contextGetUserInfo(r).ID, input.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicatePermission):
+			v.AddError("code", "this permission code already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, Envelope{"code": input.Code}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GrantUserInfoPermissionHandler grants the permission code in the URL directly to
+// the user info in the URL. Granting a code the user info already has is a no-op.
+func (app *application) GrantUserInfoPermissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, code, ok := app.readUserInfoPermissionParams(w, r)
+	if !ok {
+		return
+	}
+
+	permissions, err := app.models.Permissions.ListAll()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !permissions.Include(code) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Permissions.AddForUserAudited(app.contextGetUserInfo(r).ID, id, code)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.writeUserInfoAccess(w, r, id)
+}
+
+// RevokeUserInfoPermissionHandler revokes a permission code granted directly to the
+// user info in the URL. Codes the user info only has through a role can't be revoked
+// here; change its roles instead.
+func (app *application) RevokeUserInfoPermissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, code, ok := app.readUserInfoPermissionParams(w, r)
+	if !ok {
+		return
+	}
+
+	granted, err := app.models.Permissions.GetGrantedForUserInfo(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !granted.Include(code) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Permissions.RemoveForUserAudited(app.contextGetUserInfo(r).ID, id, code)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.writeUserInfoAccess(w, r, id)
+}
+
+// readUserInfoPermissionParams reads the :id and :code URL parameters and checks that
+// the user info exists. If it returns false a response has already been sent.
+func (app *application) readUserInfoPermissionParams(w http.ResponseWriter, r *http.Request) (int64, string, bool) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return 0, "", false
+	}
+
+	_, err = app.models.UserInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return 0, "", false
+	}
+
+	code := httprouter.ParamsFromContext(r.Context()).ByName("code")
+	return id, code, true
+}
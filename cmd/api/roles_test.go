@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// The admin routes are guarded by the permissions of the roles, so an account is let
+// in once it's given the admin role, without logging in again.
+func TestSetUserInfoRoles(t *testing.T) {
+	ts := newTestServer(t)
+	admin := ts.loginAsAdmin(t)
+	alice := ts.createUserInfo(t, "alice@example.com", "pa55word-alice", "user")
+	token := ts.login(t, "alice@example.com", "pa55word-alice")
+
+	adminRoutes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/v1/roles"},
+		{http.MethodGet, "/v1/user-infos"},
+		{http.MethodGet, "/v1/permissions"},
+	}
+	for _, route := range adminRoutes {
+		status, _ := ts.do(t, route.method, route.path, token, nil)
+		if status != http.StatusForbidden {
+			t.Fatalf("%s %s as a user: got status %d; want %d", route.method, route.path, status, http.StatusForbidden)
+		}
+	}
+
+	path := fmt.Sprintf("/v1/user-infos/%d/roles", alice.ID)
+	status, body := ts.do(t, http.MethodPut, path, token, map[string][]string{"roles": {"admin"}})
+	if status != http.StatusForbidden {
+		t.Fatalf("PUT %s as a user: got status %d; want %d", path, status, http.StatusForbidden)
+	}
+	status, body = ts.do(t, http.MethodPut, path, admin, map[string][]string{"roles": {"admin", "unknown"}})
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("PUT %s with an unknown role: got status %d; want %d: %v", path, status, http.StatusUnprocessableEntity, body)
+	}
+	status, body = ts.do(t, http.MethodPut, path, admin, map[string][]string{"roles": {"admin"}})
+	if status != http.StatusOK {
+		t.Fatalf("PUT %s: got status %d; want %d: %v", path, status, http.StatusOK, body)
+	}
+	if roles, _ := body["roles"].([]any); len(roles) != 1 || roles[0] != "admin" {
+		t.Fatalf("got roles %v; want [admin]", body["roles"])
+	}
+
+	for _, route := range adminRoutes {
+		status, _ := ts.do(t, route.method, route.path, token, nil)
+		if status != http.StatusOK {
+			t.Fatalf("%s %s as an admin: got status %d; want %d", route.method, route.path, status, http.StatusOK)
+		}
+	}
+
+	// The role filter of the listing follows the new role.
+	status, body = ts.do(t, http.MethodGet, "/v1/user-infos?role=admin&sort=email", admin, nil)
+	userInfos, _ := body["user_infos"].([]any)
+	if status != http.StatusOK || len(userInfos) != 2 || userInfos[1].(map[string]any)["email"] != "alice@example.com" {
+		t.Fatalf("GET /v1/user-infos?role=admin: got status %d, %v; want both admins", status, body)
+	}
+}
+
+func TestCreateRole(t *testing.T) {
+	ts := newTestServer(t)
+	admin := ts.loginAsAdmin(t)
+
+	tests := []struct {
+		name  string
+		input map[string]any
+		want  int
+	}{
+		{"new role", map[string]any{"name": "auditor", "permissions": []string{"roles:read"}}, http.StatusCreated},
+		{"duplicate name", map[string]any{"name": "auditor"}, http.StatusUnprocessableEntity},
+		{"unknown code", map[string]any{"name": "reviewer", "permissions": []string{"unknown:code"}}, http.StatusUnprocessableEntity},
+		{"no name", map[string]any{"permissions": []string{"roles:read"}}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		status, body := ts.do(t, http.MethodPost, "/v1/roles", admin, tt.input)
+		if status != tt.want {
+			t.Fatalf("%s: got status %d; want %d: %v", tt.name, status, tt.want, body)
+		}
+	}
+
+	// The new role can be assigned and grants its codes.
+	bob := ts.createUserInfo(t, "bob@example.com", "pa55word-bob", "user")
+	token := ts.login(t, "bob@example.com", "pa55word-bob")
+	status, body := ts.do(t, http.MethodPut, fmt.Sprintf("/v1/user-infos/%d/roles", bob.ID), admin, map[string][]string{"roles": {"user", "auditor"}})
+	if status != http.StatusOK {
+		t.Fatalf("assign auditor: got status %d; want %d: %v", status, http.StatusOK, body)
+	}
+	status, _ = ts.do(t, http.MethodGet, "/v1/roles", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("GET /v1/roles as an auditor: got status %d; want %d", status, http.StatusOK)
+	}
+	status, _ = ts.do(t, http.MethodPost, "/v1/roles", token, map[string]any{"name": "other"})
+	if status != http.StatusForbidden {
+		t.Fatalf("POST /v1/roles as an auditor: got status %d; want %d", status, http.StatusForbidden)
+	}
+}
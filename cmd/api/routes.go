@@ -14,24 +14,59 @@ func (app *application) routes() http.Handler {
 	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
 
 	// user-info
-	router.HandlerFunc(http.MethodPost, "/v1/user-infos", app.RegisterUserInfoHandler)                             // register
-	router.HandlerFunc(http.MethodPost, "/v1/user-infos/activated", app.ActivateUserInfoHandler)                   // activate
-	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication", app.CreateAuthenticationTokenHandlerUserInfo) // authenticate
-	router.HandlerFunc(http.MethodGet, "/v1/user-infos/:id", app.requireActivatedUserInfo(app.GetUserInfoHandler)) // get
-	router.HandlerFunc(http.MethodGet, "/v1/user-infos", app.requireActivatedUserInfo(app.GetAllUserInfoHandler))  // getAll
-	router.HandlerFunc(http.MethodPut, "/v1/user-infos/:id", app.requireAdminRole(app.EditUserInfoHandler))        // edit
-	router.HandlerFunc(http.MethodDelete, "/v1/user-infos/:id", app.requireAdminRole(app.DeleteUserInfoHandler))   // delete
+	router.HandlerFunc(http.MethodPost, "/v1/user-infos", app.RegisterUserInfoHandler)                                               // register
+	router.HandlerFunc(http.MethodPost, "/v1/user-infos/activated", app.ActivateUserInfoHandler)                                     // activate
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication", app.CreateAuthenticationTokenHandlerUserInfo)                   // authenticate
+	router.HandlerFunc(http.MethodGet, "/v1/user-infos/:id", app.requireActivatedUserInfo(app.GetUserInfoHandler))                   // get
+	router.HandlerFunc(http.MethodGet, "/v1/user-infos", app.requirePermission("user_info:read", app.GetAllUserInfoHandler))         // getAll
+	router.HandlerFunc(http.MethodPut, "/v1/user-infos/:id", app.putUserInfoHandler())                                               // edit, reset password
+	router.HandlerFunc(http.MethodPatch, "/v1/user-infos/:id", app.requirePermission("user_info:write", app.PatchUserInfoHandler))   // partial edit
+	router.HandlerFunc(http.MethodDelete, "/v1/user-infos/:id", app.requirePermission("user_info:write", app.DeleteUserInfoHandler)) // delete
+
+	// me
+	router.HandlerFunc(http.MethodGet, "/v1/me", app.requireActivatedUserInfo(app.GetMeHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/me", app.requireActivatedUserInfo(app.PatchMeHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/me/email", app.UpdateMeEmailHandler) // confirm email change
+	router.HandlerFunc(http.MethodPost, "/v1/me/mfa", app.requireActivatedUserInfo(app.StartMFAHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/me/mfa", app.requireActivatedUserInfo(app.ConfirmMFAHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/me/mfa", app.requireActivatedUserInfo(app.DisableMFAHandler))
+
+	// tokens
+	router.HandlerFunc(http.MethodDelete, "/v1/tokens/authentication", app.requireAuthenticatedUserInfo(app.DeleteAuthenticationTokenHandler))         // logout
+	router.HandlerFunc(http.MethodDelete, "/v1/tokens/authentication/all", app.requireAuthenticatedUserInfo(app.DeleteAllAuthenticationTokensHandler)) // logout everywhere
+	router.HandlerFunc(http.MethodDelete, "/v1/user-infos/:id/tokens", app.requirePermission("user_info:write", app.DeleteUserInfoTokensHandler))      // revoke user's tokens
+	router.HandlerFunc(http.MethodDelete, "/v1/user-infos/:id/lockout", app.requirePermission("user_info:write", app.UnlockUserInfoHandler))           // unlock after failed logins
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/refresh", app.RefreshAuthenticationTokenHandler)                                                   // exchange refresh token
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/mfa", app.CreateMFAAuthenticationTokenHandler)                                                     // second login step
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/password-reset", app.CreatePasswordResetTokenHandler)                                              // request password reset
 
 	//module-info
-	router.HandlerFunc(http.MethodPost, "/v1/module-infos", app.requireAdminRole(app.CreateModuleInfoHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/module-infos/:id", app.requireActivatedUserInfo(app.GetModuleInfoHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/module-infos", app.requireActivatedUserInfo(app.GetLatestFiftyModuleInfosHandler))
-	router.HandlerFunc(http.MethodPut, "/v1/module-infos/:id", app.requireAdminRole(app.EditModuleInfoHandler))
-	router.HandlerFunc(http.MethodDelete, "/v1/module-infos/:id", app.requireAdminRole(app.DeleteModuleInfoHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/module-infos", app.requirePermission("module_info:write", app.CreateModuleInfoHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/module-infos/:id", app.requirePermission("module_info:read", app.GetModuleInfoHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/module-infos", app.requirePermission("module_info:read", app.ListModuleInfosHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/module-infos/:id", app.requirePermission("module_info:write", app.EditModuleInfoHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/module-infos/:id", app.requirePermission("module_info:write", app.PatchModuleInfoHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/module-infos/:id", app.requirePermission("module_info:write", app.DeleteModuleInfoHandler))
 
 	// dep
-	router.HandlerFunc(http.MethodPost, "/v1/department-infos", app.requireAdminRole(app.createDepInfoHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/department-infos/:id", app.requireActivatedUserInfo(app.getDepartmentInfoHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/department-infos", app.requirePermission("department_info:write", app.createDepInfoHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/department-infos", app.requirePermission("department_info:read", app.listDepartmentInfosHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/department-infos/:id", app.requirePermission("department_info:read", app.getDepartmentInfoHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/department-infos/:id", app.requirePermission("department_info:write", app.updateDepartmentInfoHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/department-infos/:id", app.requirePermission("department_info:write", app.patchDepartmentInfoHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/department-infos/:id", app.requirePermission("department_info:write", app.deleteDepartmentInfoHandler))
+
+	// roles
+	router.HandlerFunc(http.MethodPost, "/v1/roles", app.requirePermission("roles:write", app.CreateRoleHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/roles", app.requirePermission("roles:read", app.ListRolesHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/user-infos/:id/roles", app.requirePermission("roles:write", app.SetUserInfoRolesHandler))
+
+	// permissions
+	router.HandlerFunc(http.MethodGet, "/v1/permissions", app.requirePermission("permissions:read", app.ListPermissionsHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/permissions", app.requirePermission("permissions:write", app.CreatePermissionHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user-infos/:id/permissions", app.requirePermission("permissions:read", app.GetUserInfoPermissionsHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/user-infos/:id/permissions/:code", app.requirePermission("permissions:write", app.GrantUserInfoPermissionHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user-infos/:id/permissions/:code", app.requirePermission("permissions:write", app.RevokeUserInfoPermissionHandler))
 
 	// users
 	//router.HandlerFunc(http.MethodPost, "/v1/users", App.registerUserHandler)
@@ -39,5 +74,21 @@ func (app *application) routes() http.Handler {
 	// Add the route for the POST /v1/tokens/authentication endpoint.
 	//router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication", App.CreateAuthenticationTokenHandler)
 	// Return the httprouter instance.
-	return app.recoverPanic(app.rateLimit(app.authenticate(router)))
+	// Every request is limited per IP address before authenticate looks its token
+	// up, and authenticated ones are limited per account as well.
+	return app.recoverPanic(app.rateLimitIP(app.authenticate(app.rateLimitUser(router))))
+}
+
+// putUserInfoHandler serves both PUT /v1/user-infos/:id and PUT
+// /v1/user-infos/password. httprouter doesn't allow a static segment next to the :id
+// wildcard, so the two routes share one registration and are told apart here.
+func (app *application) putUserInfoHandler() http.HandlerFunc {
+	edit := app.requirePermission("user_info:write", app.EditUserInfoHandler)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if httprouter.ParamsFromContext(r.Context()).ByName("id") == "password" {
+			app.UpdateUserInfoPasswordHandler(w, r)
+			return
+		}
+		edit(w, r)
+	}
 }
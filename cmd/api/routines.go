@@ -1,40 +1,55 @@
 package main
 
 import (
+	"context"
 	"github.com/bxiit/greenlight/internal/data"
 	"time"
 )
 
-func (app *application) checkAndResendActivation() {
+// checkAndResendActivation periodically issues fresh activation tokens to users whose
+// previous token expired before they activated. It returns once ctx is cancelled.
+func (app *application) checkAndResendActivation(ctx context.Context) {
 	for {
-		users, err := app.models.UserInfos.FindNotActivatedAndExpired()
+		app.resendActivation()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Hour):
+		}
+	}
+}
+
+func (app *application) resendActivation() {
+	users, err := app.models.UserInfos.FindNotActivatedAndExpired()
+	if err != nil {
+		app.logger.PrintError(err, nil)
+		return
+	}
+
+	for _, user := range users {
+		err := app.models.UserInfos.DeleteExpiredToken(user.ID)
 		if err != nil {
-			app.logger.PrintFatal(err, nil)
+			app.logger.PrintError(err, nil)
 			return
 		}
 
-		for _, user := range users {
-			err := app.models.UserInfos.DeleteExpiredToken(user.ID)
-			if err != nil {
-				return
-			}
+		token, err := app.models.Tokens.New(user.ID, 3*24*time.Hour, data.ScopeActivation)
+		if err != nil {
+			app.logger.PrintError(err, nil)
+			return
+		}
 
-			token, err := app.models.Tokens.New(user.ID, 3*24*time.Hour, data.ScopeActivation)
+		user := user
+		app.background(func() {
+			data := map[string]any{
+				"activationToken": token.Plaintext,
+				"userInfoID":      user.ID,
+			}
+			err := app.mailer.Send(user.Email, "user_welcome.tmpl", data)
 			if err != nil {
-				return
+				app.logger.PrintError(err, nil)
 			}
-
-			app.background(func() {
-				data := map[string]any{
-					"activationToken": token.Plaintext,
-					"userInfoID":      user.ID,
-				}
-				err := app.mailer.Send(user.Email, "user_welcome.tmpl", data)
-				if err != nil {
-					app.logger.PrintError(err, nil)
-				}
-			})
-		}
-		time.Sleep(time.Hour)
+		})
 	}
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func (app *application) serve() error {
+	// Parse the shutdown deadline the same way we parse the Db.MaxIdleTime setting.
+	shutdownTimeout, err := time.ParseDuration(app.config.ShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	// Use the httprouter instance returned by App.routes() as the server handler.
+	srv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", app.config.Port),
+		Handler:      app.routes(),
+		IdleTimeout:  time.Minute,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	// The gRPC server is started first so that a port which is already taken fails
+	// start up before anything else runs.
+	var grpcSrv *grpc.Server
+	var grpcHealth *health.Server
+	grpcError := make(chan error, 1)
+	if app.config.Grpc.Port != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", app.config.Grpc.Port))
+		if err != nil {
+			return err
+		}
+		grpcSrv, grpcHealth = app.grpcServer()
+
+		app.logger.PrintInfo("starting grpc server", map[string]string{
+			"addr": lis.Addr().String(),
+		})
+
+		go func() {
+			// Serve() returns nil once GracefulStop() or Stop() has been called, so
+			// anything else means the gRPC server died on its own.
+			err := grpcSrv.Serve(lis)
+			if err != nil {
+				grpcError <- err
+			}
+		}()
+	}
+
+	// The activation resender and the rate limiter eviction run until this context
+	// is cancelled. They are started through app.background() so that app.wg.Wait()
+	// below also waits for them to finish their current iteration.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.background(func() {
+		app.checkAndResendActivation(ctx)
+	})
+	if app.config.Limiter.Enabled {
+		app.background(func() {
+			app.evictRateLimitClients(ctx)
+		})
+	}
+	if grpcHealth != nil {
+		services := make([]string, 0)
+		for service := range grpcSrv.GetServiceInfo() {
+			services = append(services, service)
+		}
+		app.background(func() {
+			app.watchDatabaseHealth(ctx, grpcHealth, services)
+		})
+	}
+
+	// The shutdownError channel receives any errors returned by the graceful
+	// Shutdown() function.
+	shutdownError := make(chan error)
+
+	go func() {
+		// Listen for incoming SIGINT and SIGTERM signals and relay them to the quit
+		// channel. The channel is buffered so that signal.Notify() never blocks.
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+		// A failing gRPC server takes the HTTP server down with it, so that the
+		// process exits and can be restarted rather than half working. Its error is
+		// returned once everything has drained, so that the exit status is non-zero.
+		var grpcErr error
+		select {
+		case s := <-quit:
+			app.logger.PrintInfo("shutting down server", map[string]string{
+				"signal": s.String(),
+			})
+		case grpcErr = <-grpcError:
+			app.logger.PrintError(grpcErr, map[string]string{
+				"server": "grpc",
+			})
+		}
+
+		// Stop the activation resender and the rate limiter eviction before draining
+		// the rest of the work.
+		cancel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		// The change streams never end on their own, so they are closed first, and
+		// health checks start failing so that clients move to another instance.
+		app.moduleInfoChanges.close()
+		if grpcHealth != nil {
+			grpcHealth.Shutdown()
+		}
+
+		// Shutdown() stops accepting new connections and waits for in-flight requests
+		// to complete, while stopGRPC() does the same for RPCs under the same
+		// deadline. If Shutdown() fails (or the deadline is hit) we relay the error and
+		// skip waiting on the background goroutines.
+		grpcStopped := make(chan struct{})
+		go func() {
+			if grpcSrv != nil {
+				stopGRPC(ctx, grpcSrv)
+			}
+			close(grpcStopped)
+		}()
+
+		err := srv.Shutdown(ctx)
+		<-grpcStopped
+		if err != nil {
+			shutdownError <- err
+			return
+		}
+
+		app.logger.PrintInfo("completing background tasks", map[string]string{
+			"addr": srv.Addr,
+		})
+
+		// Block until every goroutine started through app.background() (queued
+		// emails and the activation resender) has returned.
+		app.wg.Wait()
+		shutdownError <- grpcErr
+	}()
+
+	app.logger.PrintInfo("starting server", map[string]string{
+		"addr": srv.Addr,
+		"Env":  app.config.Env,
+	})
+
+	// Calling Shutdown() makes ListenAndServe() return http.ErrServerClosed straight
+	// away, so that particular error means the graceful shutdown has started.
+	err = srv.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	err = <-shutdownError
+	if err != nil {
+		return err
+	}
+
+	app.logger.PrintInfo("stopped server", map[string]string{
+		"addr": srv.Addr,
+	})
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/data/memory"
+	"github.com/bxiit/greenlight/internal/jsonlog"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer serves app.routes() over httptest with every repository the routes need
+// kept in memory, so that the HTTP tests need neither Postgres nor a free port.
+type testServer struct {
+	*httptest.Server
+	app    *application
+	db     *memory.DB
+	mailer *fakeMailer
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	db := memory.NewDB()
+	mailer := &fakeMailer{}
+	app := &application{
+		config:            Config{Env: "testing"},
+		logger:            jsonlog.New(io.Discard, jsonlog.LevelOff),
+		models:            memory.NewModels(db),
+		mailer:            mailer,
+		accessTTL:         15 * time.Minute,
+		refreshTTL:        24 * time.Hour,
+		rateLimiters:      newClientLimiters(),
+		moduleInfoChanges: newModuleInfoFeed(),
+	}
+
+	ts := &testServer{
+		Server: httptest.NewServer(app.routes()),
+		app:    app,
+		db:     db,
+		mailer: mailer,
+	}
+	t.Cleanup(func() {
+		ts.Close()
+		app.wg.Wait()
+	})
+	return ts
+}
+
+// do sends the request with the body encoded as JSON, authenticated with the token
+// unless it is empty, and decodes the JSON response.
+func (ts *testServer) do(t *testing.T, method, path, token string, body any) (int, map[string]any) {
+	t.Helper()
+
+	var reqBody io.Reader
+	if body != nil {
+		js, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqBody = bytes.NewReader(js)
+	}
+
+	req, err := http.NewRequest(method, ts.URL+path, reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var result map[string]any
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	return res.StatusCode, result
+}
+
+// createUserInfo stores an activated user info with the roles, bypassing registration.
+func (ts *testServer) createUserInfo(t *testing.T, email, password string, roles ...string) *data.UserInfo {
+	t.Helper()
+
+	userInfo := &data.UserInfo{
+		Name:      "Test",
+		Surname:   "User",
+		Email:     email,
+		Role:      "user",
+		Activated: true,
+	}
+	err := userInfo.PasswordHashed.Set(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ts.app.models.UserInfos.Insert(userInfo, roles...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userInfo
+}
+
+// login authenticates through POST /v1/tokens/authentication and returns the access
+// token.
+func (ts *testServer) login(t *testing.T, email, password string) string {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodPost, "/v1/tokens/authentication", "", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("login as %s: got status %d; want %d: %v", email, status, http.StatusCreated, body)
+	}
+	return tokenIn(t, body, "authentication_token")
+}
+
+// tokenIn returns the token of the envelope key in a response body.
+func tokenIn(t *testing.T, body map[string]any, key string) string {
+	t.Helper()
+
+	token, _ := body[key].(map[string]any)["token"].(string)
+	if token == "" {
+		t.Fatalf("no %s in %v", key, body)
+	}
+	return token
+}
+
+// loginAsAdmin creates a user info with the admin role and logs in as it.
+func (ts *testServer) loginAsAdmin(t *testing.T) string {
+	t.Helper()
+
+	ts.createUserInfo(t, "admin@example.com", "pa55word-admin", "admin")
+	return ts.login(t, "admin@example.com", "pa55word-admin")
+}
+
+// sentMail is an email recorded by fakeMailer.
+type sentMail struct {
+	Recipient string
+	Template  string
+	Data      any
+}
+
+// fakeMailer records the emails instead of sending them.
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(recipient, templateFile string, data any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, sentMail{Recipient: recipient, Template: templateFile, Data: data})
+	return nil
+}
+
+// sentTo waits for the background tasks to finish, so that every email has been
+// sent, and returns the ones sent to the recipient.
+func (ts *testServer) sentTo(recipient string) []sentMail {
+	ts.app.wg.Wait()
+
+	ts.mailer.mu.Lock()
+	defer ts.mailer.mu.Unlock()
+
+	var mails []sentMail
+	for _, mail := range ts.mailer.sent {
+		if mail.Recipient == recipient {
+			mails = append(mails, mail)
+		}
+	}
+	return mails
+}
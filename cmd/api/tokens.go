@@ -1,16 +1,26 @@
 package main
 
 import (
+	"encoding/hex"
 	"errors"
 	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/jwtauth"
 	"github.com/bxiit/greenlight/internal/validator"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 type THandler interface {
 	CreateAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request)
 	CreateAuthenticationTokenHandlerUserInfo(w http.ResponseWriter, r *http.Request)
+	RefreshAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request)
+	CreateMFAAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request)
+	DeleteAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request)
+	DeleteAllAuthenticationTokensHandler(w http.ResponseWriter, r *http.Request)
+	DeleteUserInfoTokensHandler(w http.ResponseWriter, r *http.Request)
+	CreatePasswordResetTokenHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type TokenHandler struct {
@@ -50,7 +60,7 @@ func (app *application) CreateAuthenticationTokenHandler(w http.ResponseWriter,
 		return
 	}
 	// Check if the provided password matches the actual password for the user.
-	match, err := user.Password.Matches(input.Password)
+	match, _, err := user.Password.Matches(input.Password)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -97,21 +107,34 @@ func (app *application) CreateAuthenticationTokenHandlerUserInfo(w http.Response
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
+	// Refuse to check the password while the email or the client address is backing
+	// off or locked after earlier failures.
+	wait, err := app.loginRetryAfter(r, input.Email)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if wait > 0 {
+		app.loginThrottledResponse(w, r, int(math.Ceil(wait.Seconds())))
+		return
+	}
 	// Lookup the user record based on the email address. If no matching user was
 	// found, then we call the App.invalidCredentialsResponse() helper to send a 401
 	// Unauthorized response to the client (we will create this helper in a moment).
+	// The failure is counted either way so that the response doesn't reveal whether
+	// the account exists.
 	userInfo, err := app.models.UserInfos.GetByEmail(input.Email)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.invalidCredentialsResponse(w, r)
+			app.failedLoginResponse(w, r, input.Email, nil)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
 	// Check if the provided password matches the actual password for the user.
-	match, err := userInfo.PasswordHashed.Matches(input.Password)
+	match, outdated, err := userInfo.PasswordHashed.Matches(input.Password)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -119,23 +142,338 @@ func (app *application) CreateAuthenticationTokenHandlerUserInfo(w http.Response
 	// If the passwords don't match, then we call the App.invalidCredentialsResponse()
 	// helper again and return.
 	if !match {
-		app.invalidCredentialsResponse(w, r)
+		app.failedLoginResponse(w, r, input.Email, userInfo)
 		return
 	}
-	// Otherwise, if the password is correct, we generate a new token with a 24-hour
-	// expiry time and the scope 'authentication'.
-	token, err := app.models.Tokens.New(userInfo.ID, 24*time.Hour, data.ScopeAuthentication)
+	err = app.models.LoginFailures.Reset(emailLoginKey(input.Email))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	// Hashes made by an older hasher or with weaker parameters are replaced now,
+	// while the plaintext is at hand. Failing to do so mustn't fail the login.
+	if outdated {
+		err = userInfo.PasswordHashed.Set(input.Password)
+		if err == nil {
+			err = app.models.UserInfos.Update(userInfo)
+		}
+		if err != nil && !errors.Is(err, data.ErrEditConflict) {
+			app.logger.PrintError(err, map[string]string{"user_info_id": strconv.FormatInt(userInfo.ID, 10)})
+		}
+	}
+	// User infos with two-factor authentication get a short-lived mfa-pending token
+	// instead, which CreateMFAAuthenticationTokenHandler exchanges together with a
+	// code for the real tokens.
+	mfa, err := app.models.MFA.Get(userInfo.ID)
+	if err != nil && !errors.Is(err, data.ErrRecordNotFound) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if mfa != nil && mfa.Enabled {
+		token, err := app.models.Tokens.New(userInfo.ID, 5*time.Minute, data.ScopeMFAPending)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		err = app.writeJSON(w, http.StatusAccepted, Envelope{"mfa_token": token}, nil)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	// Otherwise, if the password is correct, we issue a short-lived access token
+	// together with the refresh token which can be exchanged for the next one.
+	app.issueTokenPair(w, r, userInfo)
+}
+
+// issueTokenPair starts a new token family for the user info and sends its access and
+// refresh token.
+func (app *application) issueTokenPair(w http.ResponseWriter, r *http.Request, userInfo *data.UserInfo) {
+	access, refresh, err := app.models.Tokens.NewPair(userInfo.ID, app.refreshTTL, func(refresh *data.Token) (*data.Token, error) {
+		return app.newAccessToken(userInfo, refresh)
+	})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	// Encode the token to JSON and send it in the response along with a 201 Created
-	// status code.
-	err = app.writeJSON(w, http.StatusCreated, Envelope{
-		"authentication_token": token,
+	app.writeTokenPair(w, r, access, refresh)
+}
+
+// failedLoginResponse counts the failed login and sends the usual invalid credentials
+// response.
+func (app *application) failedLoginResponse(w http.ResponseWriter, r *http.Request, email string, userInfo *data.UserInfo) {
+	err := app.recordLoginFailure(r, email, userInfo)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	app.invalidCredentialsResponse(w, r)
+}
+
+// RefreshAuthenticationTokenHandler exchanges a refresh token for a new access token
+// and a new refresh token. Refresh tokens are single use; presenting one twice
+// revokes every token issued from the same login.
+func (app *application) RefreshAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if data.ValidateTokenPlaintext(v, input.RefreshToken); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	// Rotate calls the issuer once it has checked the old refresh token, but before
+	// the transaction which marks it used and stores the new tokens. Failing to issue
+	// the access token therefore leaves the old refresh token valid for another try.
+	// A user info deleted in the meantime gives ErrRecordNotFound like an unknown
+	// token.
+	access, refresh, err := app.models.Tokens.Rotate(input.RefreshToken, app.refreshTTL, func(refresh *data.Token) (*data.Token, error) {
+		userInfo, err := app.models.UserInfos.Get(refresh.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return app.newAccessToken(userInfo, refresh)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.invalidRefreshTokenResponse(w, r)
+		case errors.Is(err, data.ErrTokenReused):
+			app.logger.PrintInfo("refresh token reused, token family revoked", map[string]string{
+				"ip": app.clientIP(r),
+			})
+			app.invalidRefreshTokenResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	app.writeTokenPair(w, r, access, refresh)
+}
+
+// newAccessToken issues an access token in the family of a refresh token. In jwt mode
+// it is a signed token carrying the role and permissions of the user info, otherwise
+// it is a random token which the repository stores alongside the refresh token.
+func (app *application) newAccessToken(userInfo *data.UserInfo, refresh *data.Token) (*data.Token, error) {
+	family := refresh.Family
+	if app.jwtKeys == nil {
+		access, err := data.GenerateToken(userInfo.ID, app.accessTTL, data.ScopeAuthentication)
+		if err != nil {
+			return nil, err
+		}
+		access.Family = family
+		return access, nil
+	}
+
+	permissions, err := app.models.Permissions.GetAllForUserInfo(userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	missing, err := app.mfaMissing(userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if missing || permissions == nil {
+		permissions = data.Permissions{}
+	}
+
+	signed, expiry, err := app.jwtKeys.Sign(userInfo.ID, app.accessTTL, jwtauth.Claims{
+		Role:        userInfo.Role,
+		Activated:   userInfo.Activated,
+		Permissions: permissions,
+		SessionID:   hex.EncodeToString(family),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.Token{
+		Plaintext: signed,
+		UserID:    userInfo.ID,
+		Expiry:    expiry,
+		Scope:     data.ScopeAuthentication,
+		Family:    family,
+	}, nil
+}
+
+// writeTokenPair sends a newly issued access and refresh token in a 201 Created
+// response.
+func (app *application) writeTokenPair(w http.ResponseWriter, r *http.Request, access, refresh *data.Token) {
+	err := app.writeJSON(w, http.StatusCreated, Envelope{
+		"authentication_token": access,
+		"refresh_token":        refresh,
 	}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
+
+// DeleteAuthenticationTokenHandler revokes the bearer token that was used to
+// authenticate the request together with the refresh token issued alongside it, i.e.
+// it logs the current session out.
+func (app *application) DeleteAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// The authenticate middleware has already checked the header, so the token is
+	// guaranteed to be present here.
+	token, _ := bearerToken(r)
+
+	// A signed access token can't be revoked before it expires, so in jwt mode only
+	// the refresh token family it was issued with is deleted.
+	if app.jwtKeys != nil {
+		claims, err := app.jwtKeys.Parse(token)
+		if err != nil {
+			app.invalidAuthenticationTokenResponse(w, r)
+			return
+		}
+		family, err := hex.DecodeString(claims.SessionID)
+		if err != nil || len(family) == 0 {
+			app.invalidAuthenticationTokenResponse(w, r)
+			return
+		}
+		err = app.models.Tokens.DeleteFamily(family)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		err = app.writeJSON(w, http.StatusOK, Envelope{"message": "authentication token successfully revoked"}, nil)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err := app.models.Tokens.DeleteFamilyForToken(data.ScopeAuthentication, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.invalidAuthenticationTokenResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "authentication token successfully revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteAllAuthenticationTokensHandler revokes every authentication token belonging
+// to the current user, logging them out of all their sessions.
+func (app *application) DeleteAllAuthenticationTokensHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo := app.contextGetUserInfo(r)
+
+	err := app.models.Tokens.DeleteAllForUser(data.ScopeAuthentication, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeRefresh, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "all authentication tokens successfully revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteUserInfoTokensHandler lets an admin revoke every authentication token of the
+// user info named in the URL.
+func (app *application) DeleteUserInfoTokensHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.UserInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeAuthentication, id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeRefresh, id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"message": "all authentication tokens of the user info successfully revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreatePasswordResetTokenHandler generates a password reset token and emails it to
+// the user info with the given address.
+func (app *application) CreatePasswordResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate the user's email address.
+	var input struct {
+		Email string `json:"email"`
+	}
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	v := validator.New()
+	if data.ValidateEmail(v, input.Email); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+	// Try to retrieve the corresponding user record for the email address. Unknown
+	// and inactive accounts get the same response as active ones, so that the
+	// endpoint doesn't reveal which email addresses are registered; they just don't
+	// get an email.
+	userInfo, err := app.models.UserInfos.GetByEmail(input.Email)
+	if err != nil && !errors.Is(err, data.ErrRecordNotFound) {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if err == nil && userInfo.Activated {
+		// Create a new password reset token with a 45-minute expiry time.
+		token, err := app.models.Tokens.New(userInfo.ID, 45*time.Minute, data.ScopePasswordReset)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		// Email the user with their password reset token.
+		app.background(func() {
+			data := map[string]any{
+				"passwordResetToken": token.Plaintext,
+			}
+			err := app.mailer.Send(userInfo.Email, "token_password_reset.tmpl", data)
+			if err != nil {
+				app.logger.PrintError(err, nil)
+			}
+		})
+	}
+	// Send a 202 Accepted response and confirmation message to the client.
+	env := Envelope{"message": "if an account with this email address exists, an email has been sent to it containing password reset instructions"}
+	err = app.writeJSON(w, http.StatusAccepted, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Exchanging a refresh token gives a working access token and a new refresh token,
+// and presenting the old refresh token again revokes both.
+func TestRefreshAuthenticationToken(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+
+	status, body := ts.do(t, http.MethodPost, "/v1/tokens/authentication", "", map[string]string{
+		"email":    "alice@example.com",
+		"password": "pa55word-alice",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("login: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	first := tokenIn(t, body, "refresh_token")
+
+	status, body = ts.do(t, http.MethodPost, "/v1/tokens/refresh", "", map[string]string{"refresh_token": first})
+	if status != http.StatusCreated {
+		t.Fatalf("refresh: got status %d; want %d: %v", status, http.StatusCreated, body)
+	}
+	access := tokenIn(t, body, "authentication_token")
+	second := tokenIn(t, body, "refresh_token")
+
+	status, _ = ts.do(t, http.MethodGet, "/v1/me", access, nil)
+	if status != http.StatusOK {
+		t.Fatalf("GET /v1/me with the new access token: got status %d; want %d", status, http.StatusOK)
+	}
+
+	status, _ = ts.do(t, http.MethodPost, "/v1/tokens/refresh", "", map[string]string{"refresh_token": first})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("refresh with a used token: got status %d; want %d", status, http.StatusUnauthorized)
+	}
+	status, _ = ts.do(t, http.MethodPost, "/v1/tokens/refresh", "", map[string]string{"refresh_token": second})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("refresh after the reuse: got status %d; want %d", status, http.StatusUnauthorized)
+	}
+	status, _ = ts.do(t, http.MethodGet, "/v1/me", access, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("GET /v1/me after the reuse: got status %d; want %d", status, http.StatusUnauthorized)
+	}
+}
+
+// Requesting a password reset gives the same response whether or not an activated
+// account has the email, and only an activated account is sent the token.
+func TestCreatePasswordResetToken(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUserInfo(t, "alice@example.com", "pa55word-alice")
+	inactive := ts.createUserInfo(t, "bob@example.com", "pa55word-bob")
+	inactive.Activated = false
+	err := ts.app.models.UserInfos.Update(inactive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		email string
+		mails int
+	}{
+		{"alice@example.com", 1},
+		{"bob@example.com", 0},
+		{"nobody@example.com", 0},
+	}
+	for _, tt := range tests {
+		status, body := ts.do(t, http.MethodPost, "/v1/tokens/password-reset", "", map[string]string{"email": tt.email})
+		if status != http.StatusAccepted || body["message"] != "if an account with this email address exists, an email has been sent to it containing password reset instructions" {
+			t.Fatalf("%s: got status %d, %v; want %d", tt.email, status, body, http.StatusAccepted)
+		}
+		if mails := ts.sentTo(tt.email); len(mails) != tt.mails {
+			t.Fatalf("%s: got %d emails; want %d", tt.email, len(mails), tt.mails)
+		}
+	}
+}
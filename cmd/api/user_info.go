@@ -1,39 +1,32 @@
 package main
 
 import (
-	"context"
 	"errors"
 	"github.com/bxiit/greenlight/internal/data"
 	"github.com/bxiit/greenlight/internal/validator"
-	pb "github.com/bxiit/greenlight/rpc"
 	"net/http"
 	"time"
 )
 
-type UserService struct {
-	pb.UnimplementedUserServiceServer
-}
-
 type UIHandler interface {
 	ActivateUserInfoHandler(w http.ResponseWriter, r *http.Request)
 	RegisterUserInfoHandler(w http.ResponseWriter, r *http.Request)
 	GetUserInfoHandler(w http.ResponseWriter, r *http.Request)
 	GetAllUserInfoHandler(w http.ResponseWriter, r *http.Request)
 	EditUserInfoHandler(w http.ResponseWriter, r *http.Request)
+	PatchUserInfoHandler(w http.ResponseWriter, r *http.Request)
 	DeleteUserInfoHandler(w http.ResponseWriter, r *http.Request)
+	UpdateUserInfoPasswordHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type UserInfoHandler struct {
 	Repo                 data.UserInfoRepository
 	TokenRepoForUI       data.TokenRepo
 	PermissionsRepoForUI data.PermissionRepo
+	RolesRepoForUI       data.RoleRepo
 	app                  *application
 }
 
-func (c *UserService) InsertUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
-	return &pb.UserResponse{Ok: req.Email == "atabekbekseiit@gmail.com"}, nil
-}
-
 func (app *application) ActivateUserInfoHandler(w http.ResponseWriter, r *http.Request) {
 	// Parse the plaintext activation token from the request body.
 	var input struct {
@@ -124,12 +117,19 @@ func (app *application) RegisterUserInfoHandler(w http.ResponseWriter, r *http.R
 	}
 
 	v := validator.New()
-	if data.ValidateUserInfo(v, userInfo); !v.Valid() {
+	data.ValidateUserInfo(v, userInfo)
+	err = data.ValidatePasswordPolicy(v, input.Password, userInfo.Email, userInfo.Name, userInfo.Surname)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	err = app.models.UserInfos.Insert(userInfo)
+	// Every new account starts out with the regular "user" role.
+	err = app.models.UserInfos.Insert(userInfo, "user")
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrDuplicateEmail):
@@ -140,11 +140,6 @@ func (app *application) RegisterUserInfoHandler(w http.ResponseWriter, r *http.R
 		}
 		return
 	}
-	err = app.models.Permissions.AddForUser(userInfo.ID, "movies:read")
-	if err != nil {
-		app.serverErrorResponse(w, r, err)
-		return
-	}
 
 	token, err := app.models.Tokens.New(userInfo.ID, 3*24*time.Hour, data.ScopeActivation)
 	if err != nil {
@@ -200,18 +195,37 @@ func (app *application) GetUserInfoHandler(w http.ResponseWriter, r *http.Reques
 }
 
 func (app *application) GetAllUserInfoHandler(w http.ResponseWriter, r *http.Request) {
-	userInfos, err := app.models.UserInfos.GetAll()
+	var input struct {
+		Search    string
+		Role      string
+		Activated *bool
+		data.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	input.Search = app.readString(qs, "search", "")
+	input.Role = app.readString(qs, "role", "")
+	input.Activated = app.readBool(qs, "activated", v)
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Filters.Sort = app.readString(qs, "sort", "id")
+	input.Filters.SortSafelist = data.UserInfoSortSafelist
+
+	if data.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	userInfos, metadata, err := app.models.UserInfos.GetAll(input.Search, input.Role, input.Activated, input.Filters)
 	if err != nil {
-		switch {
-		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
-		default:
-			app.serverErrorResponse(w, r, err)
-		}
+		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, Envelope{"user_infos": userInfos}, nil)
+	err = app.writeJSON(w, http.StatusOK, Envelope{"user_infos": userInfos, "metadata": metadata}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -262,6 +276,165 @@ func (app *application) EditUserInfoHandler(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// PatchUserInfoHandler applies a partial update: only the fields present in the
+// request body are validated and changed, the rest of the record is left as it is.
+func (app *application) PatchUserInfoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	userInfo, err := app.models.UserInfos.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	// Use pointers so that a field missing from the JSON body (nil) can be told
+	// apart from a field explicitly set to its zero value.
+	var input struct {
+		Name    *string `json:"name"`
+		Surname *string `json:"surname"`
+		Email   *string `json:"email"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if input.Name != nil {
+		data.ValidateUserInfoName(v, *input.Name)
+		userInfo.Name = *input.Name
+	}
+	if input.Surname != nil {
+		data.ValidateUserInfoSurname(v, *input.Surname)
+		userInfo.Surname = *input.Surname
+	}
+	if input.Email != nil {
+		data.ValidateEmail(v, *input.Email)
+		userInfo.Email = *input.Email
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.UserInfos.Update(userInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateEmail):
+			v.AddError("email", "a user with this email address already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, Envelope{"userInfo": userInfo}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateUserInfoPasswordHandler sets a new password for the user info owning the
+// password reset token, then revokes every authentication token of that user so that
+// existing sessions have to log in again.
+func (app *application) UpdateUserInfoPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate the user's new password and password reset token.
+	var input struct {
+		Password       string `json:"password"`
+		TokenPlaintext string `json:"token"`
+	}
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	v := validator.New()
+	data.ValidatePasswordPlaintext(v, input.Password)
+	data.ValidateTokenPlaintext(v, input.TokenPlaintext)
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+	// Retrieve the details of the user associated with the password reset token,
+	// returning an error message if no matching record was found.
+	userInfo, err := app.models.UserInfos.GetForToken(data.ScopePasswordReset, input.TokenPlaintext)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("token", "invalid or expired password reset token")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	// The policy can only be checked now that we know whose password it is.
+	err = data.ValidatePasswordPolicy(v, input.Password, userInfo.Email, userInfo.Name, userInfo.Surname)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+	// Set the new password for the user.
+	err = userInfo.PasswordHashed.Set(input.Password)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	// Save the updated user record in our database, checking for any edit conflicts as
+	// normal.
+	err = app.models.UserInfos.Update(userInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	// If everything was successful, then delete all password reset tokens and revoke
+	// all authentication and refresh tokens for the user.
+	err = app.models.Tokens.DeleteAllForUser(data.ScopePasswordReset, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeAuthentication, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	err = app.models.Tokens.DeleteAllForUser(data.ScopeRefresh, userInfo.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	// Send the user a confirmation message.
+	env := Envelope{"message": "your password was successfully reset"}
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
 func (app *application) DeleteUserInfoHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := app.readIDParam(r)
 	if err != nil {
@@ -375,12 +548,19 @@ func (ui *UserInfoHandler) RegisterUserInfoHandler(w http.ResponseWriter, r *htt
 	}
 
 	v := validator.New()
-	if data.ValidateUserInfo(v, userInfo); !v.Valid() {
+	data.ValidateUserInfo(v, userInfo)
+	err = data.ValidatePasswordPolicy(v, input.Password, userInfo.Email, userInfo.Name, userInfo.Surname)
+	if err != nil {
+		ServerErrorResponse(w, r, err)
+		return
+	}
+	if !v.Valid() {
 		FailedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	err = ui.Repo.Insert(userInfo)
+	// Every new account starts out with the regular "user" role.
+	err = ui.Repo.Insert(userInfo, "user")
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrDuplicateEmail):
@@ -392,12 +572,6 @@ func (ui *UserInfoHandler) RegisterUserInfoHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	err = ui.PermissionsRepoForUI.AddForUser(userInfo.ID, "movies:read")
-	if err != nil {
-		ServerErrorResponse(w, r, err)
-		return
-	}
-
 	token, err := ui.TokenRepoForUI.New(userInfo.ID, 3*24*time.Hour, data.ScopeActivation)
 	if err != nil {
 		ServerErrorResponse(w, r, err)
@@ -1,99 +1,18 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/bxiit/greenlight/internal/data"
-	"github.com/bxiit/greenlight/internal/jsonlog"
-	"github.com/bxiit/greenlight/internal/mailer"
 	"github.com/bxiit/greenlight/internal/validator"
-	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
-	"io"
 	"net/http"
-	"os"
-	"strings"
+	"strconv"
 	"testing"
-	"time"
 )
 
-func AuthenticateUserInfo(email, password string) string {
-	var userData struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
-
-	userData.Email = email
-	userData.Password = password
-
-	userDataJson, err := json.Marshal(userData)
-	if err != nil {
-		fmt.Println("Error marshalling user data:", err)
-		return ""
-	}
-
-	request, err := http.NewRequest(http.MethodPost,
-		"http://localhost:4002/v1/tokens/authentication",
-		strings.NewReader(string(userDataJson)))
-	if err != nil {
-		return ""
-	}
-
-	request.Header.Add("Content-Type", "application/json")
-
-	client := &http.Client{
-		Timeout: time.Minute,
-	}
-
-	response, err := client.Do(request)
-	if err != nil {
-		fmt.Printf("Error making request: %v\n", err)
-		return ""
-	}
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return ""
-	}
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		fmt.Printf("Error unmarshalling response body: %v\n", err)
-		return ""
-	}
-
-	authToken, exists := result["authentication_token"]
-	if !exists {
-		fmt.Println("Authentication authToken not found in the response")
-		return ""
-	}
-
-	var authTokenMap, ok = authToken.(map[string]interface{})
-	if !ok {
-		fmt.Println("Authentication token is not a map")
-		return ""
-	}
-
-	token, tokenExists := authTokenMap["token"]
-	if !tokenExists {
-		fmt.Println("Authentication token is not a map")
-		return ""
-	}
-
-	tokenValue, ok := token.(string)
-	if !ok {
-		fmt.Println("Interface value is not a string")
-		return ""
-	}
-	return tokenValue
-}
-
 type Suite struct {
 	suite.Suite
-	Server *http.Server
-	Client *http.Client
+	Server *testServer
 	App    *application
 }
 
@@ -101,56 +20,31 @@ func TestSuite(t *testing.T) {
 	suite.Run(t, &Suite{})
 }
 
-func (s *Suite) SetupSuite() {
-	var cfg Config
-	viper.SetConfigFile("./config.json")
-	err := viper.ReadInConfig()
-	s.Nil(err)
-
-	err = viper.Unmarshal(&cfg)
-	s.Nil(err)
-
-	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
-
-	db, err := openDB(cfg)
-	if err != nil {
-		logger.PrintFatal(err, nil)
-	}
-
-	app := &application{
-		config: cfg,
-		logger: logger,
-		models: data.NewModels(db), // data.NewModels() function to initialize App Models struct
-		mailer: mailer.New(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.Smtp.Sender),
-	}
-
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: app.routes(),
-	}
-
-	go func() {
-		err := server.ListenAndServe()
-		if err != nil {
-			_ = fmt.Errorf("failed to listen")
-		}
-	}()
-	s.Server = server
-	s.Client = &http.Client{}
-	s.App = app
+func (s *Suite) SetupTest() {
+	s.Server = newTestServer(s.T())
+	s.App = s.Server.app
 }
 
 func (s *Suite) TestUserInfoModel_GetUserInfo() {
-	userInfo, err := s.App.models.UserInfos.Get(12)
+	created := s.Server.createUserInfo(s.T(), "alice@example.com", "pa55word-alice")
+
+	userInfo, err := s.App.models.UserInfos.Get(created.ID)
 	s.Nil(err)
 
-	s.Equal(12, int(userInfo.ID))
+	s.Equal(created.ID, userInfo.ID)
+	s.Equal("alice@example.com", userInfo.Email)
 }
 
 func (s *Suite) TestUserInfoRepo_GetAllUserInfo() {
-	userInfos, err := s.App.models.UserInfos.GetAll()
+	for i := 0; i < 3; i++ {
+		s.Server.createUserInfo(s.T(), "user"+strconv.Itoa(i)+"@example.com", "pa55word-user")
+	}
+
+	filters := data.Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: data.UserInfoSortSafelist}
+	userInfos, metadata, err := s.App.models.UserInfos.GetAll("", "", nil, filters)
 	s.Nil(err)
-	s.Equal(13, len(userInfos))
+	s.Equal(3, len(userInfos))
+	s.Equal(3, metadata.TotalRecords)
 }
 
 func (s *Suite) TestUserInfo_DeleteUserInfo_InvalidId() {
@@ -177,6 +71,8 @@ func (s *Suite) TestUserInfo_ValidateUserInfo() {
 }
 
 func (s *Suite) TestUserInfo_UpdateUserInfo() {
+	s.Server.createUserInfo(s.T(), "test2ass3@example.com", "pa55word-test")
+
 	ui, err := s.App.models.UserInfos.GetByEmail("test2ass3@example.com")
 	s.Nil(err)
 
@@ -191,3 +87,38 @@ func (s *Suite) TestUserInfo_UpdateUserInfo() {
 	s.Equal("test2ass3@example.com", ui.Email)
 	s.Equal("test updated name", ui.Name)
 }
+
+func (s *Suite) TestUserInfo_RegisterAndActivate() {
+	status, body := s.Server.do(s.T(), http.MethodPost, "/v1/user-infos", "", map[string]string{
+		"name":     "Bob",
+		"surname":  "Smith",
+		"email":    "bob@example.com",
+		"password": "correct-horse-battery",
+	})
+	s.Require().Equal(http.StatusAccepted, status, body)
+
+	mails := s.Server.sentTo("bob@example.com")
+	s.Require().Len(mails, 1)
+	s.Equal("user_welcome.tmpl", mails[0].Template)
+	token, _ := mails[0].Data.(map[string]any)["activationToken"].(string)
+	s.Require().NotEmpty(token)
+
+	// Not activated yet, so the login succeeds but the account is of no use.
+	accessToken := s.Server.login(s.T(), "bob@example.com", "correct-horse-battery")
+	status, _ = s.Server.do(s.T(), http.MethodGet, "/v1/me", accessToken, nil)
+	s.Equal(http.StatusForbidden, status)
+
+	status, body = s.Server.do(s.T(), http.MethodPost, "/v1/user-infos/activated", "", map[string]string{
+		"token": token,
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.Server.do(s.T(), http.MethodGet, "/v1/me", accessToken, nil)
+	s.Equal(http.StatusOK, status, body)
+
+	// The activation token is single use.
+	status, _ = s.Server.do(s.T(), http.MethodPost, "/v1/user-infos/activated", "", map[string]string{
+		"token": token,
+	})
+	s.Equal(http.StatusUnprocessableEntity, status)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/validator"
+	pb "github.com/bxiit/greenlight/rpc"
+	"time"
+)
+
+// UserService serves rpc.UserService from the same models as the /v1/user-infos
+// handlers, with the same validation.
+type UserService struct {
+	pb.UnimplementedUserServiceServer
+	app *application
+}
+
+func (s *UserService) InsertUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
+	app := s.app
+
+	userInfo := &data.UserInfo{
+		Name:      req.Name,
+		Surname:   req.Surname,
+		Email:     req.Email,
+		Activated: false,
+		Role:      "user",
+	}
+
+	err := userInfo.PasswordHashed.Set(req.Password)
+	if err != nil {
+		return nil, app.serverErrorStatus(ctx, err)
+	}
+
+	v := validator.New()
+	data.ValidateUserInfo(v, userInfo)
+	err = data.ValidatePasswordPolicy(v, req.Password, userInfo.Email, userInfo.Name, userInfo.Surname)
+	if err != nil {
+		return nil, app.serverErrorStatus(ctx, err)
+	}
+	if !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	// Every new account starts out with the regular "user" role.
+	err = app.models.UserInfos.Insert(userInfo, "user")
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateEmail):
+			return nil, duplicateEmailStatus()
+		default:
+			return nil, app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	token, err := app.models.Tokens.New(userInfo.ID, 3*24*time.Hour, data.ScopeActivation)
+	if err != nil {
+		return nil, app.serverErrorStatus(ctx, err)
+	}
+
+	app.background(func() {
+		data := map[string]any{
+			"activationToken": token.Plaintext,
+			"userInfoID":      userInfo.ID,
+		}
+		err := app.mailer.Send(userInfo.Email, "user_welcome.tmpl", data)
+		if err != nil {
+			app.logger.PrintError(err, nil)
+		}
+	})
+
+	return &pb.UserResponse{Ok: true, User: userInfoToProto(userInfo)}, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
+	userInfo, err := s.app.models.UserInfos.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return &pb.UserResponse{Ok: true, User: userInfoToProto(userInfo)}, nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	filters := data.Filters{
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		Sort:         req.Sort,
+		SortSafelist: data.UserInfoSortSafelist,
+	}
+	// Zero values mean the field wasn't set; use the same defaults as the query
+	// string parameters of GET /v1/user-infos.
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = 20
+	}
+	if filters.Sort == "" {
+		filters.Sort = "id"
+	}
+
+	v := validator.New()
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	userInfos, metadata, err := s.app.models.UserInfos.GetAll(req.Search, req.Role, req.Activated, filters)
+	if err != nil {
+		return nil, s.app.serverErrorStatus(ctx, err)
+	}
+
+	resp := &pb.ListUsersResponse{
+		Users:    make([]*pb.UserInfo, 0, len(userInfos)),
+		Metadata: metadataToProto(metadata),
+	}
+	for _, userInfo := range userInfos {
+		resp.Users = append(resp.Users, userInfoToProto(userInfo))
+	}
+	return resp, nil
+}
+
+// UpdateUser works like PatchUserInfoHandler: unset fields are left as they are.
+func (s *UserService) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	app := s.app
+
+	userInfo, err := app.models.UserInfos.Get(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	v := validator.New()
+	if req.Name != nil {
+		data.ValidateUserInfoName(v, *req.Name)
+		userInfo.Name = *req.Name
+	}
+	if req.Surname != nil {
+		data.ValidateUserInfoSurname(v, *req.Surname)
+		userInfo.Surname = *req.Surname
+	}
+	if req.Email != nil {
+		data.ValidateEmail(v, *req.Email)
+		userInfo.Email = *req.Email
+	}
+	if !v.Valid() {
+		return nil, failedValidationStatus(v.Errors)
+	}
+
+	err = app.models.UserInfos.Update(userInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateEmail):
+			return nil, duplicateEmailStatus()
+		case errors.Is(err, data.ErrEditConflict):
+			return nil, editConflictStatus()
+		default:
+			return nil, app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return &pb.UserResponse{Ok: true, User: userInfoToProto(userInfo)}, nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	err := s.app.models.UserInfos.Delete(req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			return nil, notFoundStatus()
+		default:
+			return nil, s.app.serverErrorStatus(ctx, err)
+		}
+	}
+
+	return &pb.DeleteUserResponse{}, nil
+}
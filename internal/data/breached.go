@@ -0,0 +1,176 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxBreachedLine bounds a line of the breached passwords file: 40 hex digits, a colon,
+// the count and the line ending.
+const maxBreachedLine = 128
+
+// breachedPrefixLen is the number of hex digits of the SHA-1 hash naming a bucket of
+// the prefix layout, as in the range API of Have I Been Pwned.
+const breachedPrefixLen = 5
+
+// BreachedPasswords looks up passwords in a local copy of a breached password list
+// from Have I Been Pwned, in one of two layouts:
+//
+//   - A directory of prefix buckets, as the range API serves them and the Pwned
+//     Passwords downloader stores them: the file "<PREFIX>.txt" holds the hashes
+//     starting with those 5 hex digits, one "<remaining 35 hex digits>:<count>" per
+//     line. A lookup reads the one bucket, so only the prefix of the hash decides
+//     which part of the list is touched.
+//   - A single file with one "<SHA-1 in upper case hex>[:<count>]" line per password,
+//     sorted by hash, such as the "ordered by hash" download. A lookup is a binary
+//     search over the file without loading it into memory.
+type BreachedPasswords struct {
+	dir  string
+	file *os.File
+	size int64
+}
+
+// OpenBreachedPasswords opens the breached passwords directory or file at path.
+func OpenBreachedPasswords(path string) (*BreachedPasswords, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &BreachedPasswords{dir: path}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BreachedPasswords{file: file, size: info.Size()}, nil
+}
+
+// Close closes the underlying file, if any.
+func (b *BreachedPasswords) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	return b.file.Close()
+}
+
+// Contains reports whether the plaintext password is in the list.
+func (b *BreachedPasswords) Contains(plaintext string) (bool, error) {
+	sum := sha1.Sum([]byte(plaintext))
+	target := []byte(strings.ToUpper(hex.EncodeToString(sum[:])))
+	if b.dir != "" {
+		return b.bucketContains(target)
+	}
+
+	// lo is always the start of a line whose hash sorts before the target (or 0) and
+	// hi the start of a line whose hash doesn't (or the end of the file), so the
+	// target can only be on the lines from lo up to and including the one at hi.
+	lo, hi := int64(0), b.size
+	for hi-lo > 4096 {
+		mid := lo + (hi-lo)/2
+		start, line, err := b.lineFrom(mid)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case start >= hi:
+			hi = mid
+		case bytes.Compare(breachedHash(line), target) < 0:
+			lo = start
+		default:
+			hi = start
+		}
+	}
+
+	end := hi + maxBreachedLine
+	if end > b.size {
+		end = b.size
+	}
+	buf := make([]byte, end-lo)
+	_, err := b.file.ReadAt(buf, lo)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		if bytes.Equal(breachedHash(line), target) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bucketContains looks the hash up in the bucket of its prefix. A missing bucket is
+// reported as an error rather than as the hash not being breached, as it means the
+// download is incomplete.
+func (b *BreachedPasswords) bucketContains(target []byte) (bool, error) {
+	prefix, suffix := target[:breachedPrefixLen], target[breachedPrefixLen:]
+	bucket, err := os.ReadFile(filepath.Join(b.dir, string(prefix)+".txt"))
+	if err != nil {
+		return false, fmt.Errorf("breached passwords: %w", err)
+	}
+	for _, line := range bytes.Split(bucket, []byte("\n")) {
+		if bytes.Equal(breachedHash(line), suffix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lineFrom returns the first line starting at or after offset, together with its
+// start. At the end of the file it returns the file size and an empty line.
+func (b *BreachedPasswords) lineFrom(offset int64) (int64, []byte, error) {
+	start := offset
+	if offset > 0 {
+		buf, err := b.readAt(offset - 1)
+		if err != nil {
+			return 0, nil, err
+		}
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			if offset-1+int64(len(buf)) >= b.size {
+				return b.size, nil, nil
+			}
+			return 0, nil, fmt.Errorf("breached passwords: line longer than %d bytes at offset %d", maxBreachedLine, offset)
+		}
+		start = offset + int64(i)
+	}
+	if start >= b.size {
+		return b.size, nil, nil
+	}
+
+	buf, err := b.readAt(start)
+	if err != nil {
+		return 0, nil, err
+	}
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i]
+	}
+	return start, buf, nil
+}
+
+func (b *BreachedPasswords) readAt(offset int64) ([]byte, error) {
+	buf := make([]byte, maxBreachedLine)
+	n, err := b.file.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// breachedHash returns the hash part of a line, upper cased so that files in lower
+// case hex work too.
+func breachedHash(line []byte) []byte {
+	line = bytes.TrimRight(line, "\r")
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return bytes.ToUpper(line)
+}
@@ -0,0 +1,140 @@
+// Package datatest holds the contract tests of the repository interfaces of the data
+// package. Every implementation has to pass them, so that e.g. the in-memory
+// repositories used by the HTTP tests behave like the Postgres ones in production.
+package datatest
+
+import (
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NewModels returns Models backed by a fresh store, which holds nothing but what the
+// migrations insert: the permissions and the "user" and "admin" roles.
+type NewModels func(t *testing.T) data.Models
+
+// testCase is one contract test. It gets Models of its own, so that the cases don't
+// depend on each other.
+type testCase struct {
+	name string
+	test func(t *testing.T, m data.Models)
+}
+
+// Run runs the contract tests of every repository interface against the Models
+// returned by newModels.
+func Run(t *testing.T, newModels NewModels) {
+	suites := []struct {
+		name  string
+		cases []testCase
+	}{
+		{"UserInfoRepository", userInfoCases},
+		{"ModuleInfoRepository", moduleInfoCases},
+		{"DepartmentInfoRepository", departmentInfoCases},
+		{"TokenRepository", tokenCases},
+		{"EmailChangeRepository", emailChangeCases},
+		{"PermissionRepository", permissionCases},
+		{"RoleRepository", roleCases},
+		{"PermissionAuditRepository", permissionAuditCases},
+		{"LoginFailureRepository", loginFailureCases},
+		{"MFARepository", mfaCases},
+	}
+
+	for _, suite := range suites {
+		t.Run(suite.name, func(t *testing.T) {
+			for _, tc := range suite.cases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.test(t, newModels(t))
+				})
+			}
+		})
+	}
+}
+
+const testPassword = "pa55word1234"
+
+var (
+	passwordOnce     sync.Once
+	passwordTemplate data.UserInfo
+)
+
+// withPassword sets testPassword as the password of the user info. Hashing is slow, so
+// the password is only hashed once and its hash copied into every user info.
+func withPassword(t *testing.T, userInfo *data.UserInfo) *data.UserInfo {
+	t.Helper()
+
+	passwordOnce.Do(func() {
+		err := passwordTemplate.PasswordHashed.Set(testPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	userInfo.PasswordHashed = passwordTemplate.PasswordHashed
+	return userInfo
+}
+
+// insertUserInfo stores a user info with the "user" role, the email and testPassword.
+func insertUserInfo(t *testing.T, m data.Models, email string, activated bool) *data.UserInfo {
+	t.Helper()
+
+	userInfo := withPassword(t, &data.UserInfo{
+		Name:      "Test",
+		Surname:   "User",
+		Email:     email,
+		Role:      "user",
+		Activated: activated,
+	})
+	err := m.UserInfos.Insert(userInfo)
+	if err != nil {
+		t.Fatalf("insert user info %s: %v", email, err)
+	}
+	return userInfo
+}
+
+func insertModuleInfo(t *testing.T, m data.Models, name string, duration int, examType string) *data.ModuleInfo {
+	t.Helper()
+
+	moduleInfo := &data.ModuleInfo{
+		ModuleName:     name,
+		ModuleDuration: time.Duration(duration),
+		ExamType:       examType,
+	}
+	err := m.ModuleInfos.Create(moduleInfo)
+	if err != nil {
+		t.Fatalf("create module info %s: %v", name, err)
+	}
+	return moduleInfo
+}
+
+func newToken(t *testing.T, m data.Models, userID int64, ttl time.Duration, scope string) *data.Token {
+	t.Helper()
+
+	token, err := m.Tokens.New(userID, ttl, scope)
+	if err != nil {
+		t.Fatalf("new %s token: %v", scope, err)
+	}
+	return token
+}
+
+// checkErr fails the test unless err is want, which may be nil.
+func checkErr(t *testing.T, what string, err, want error) {
+	t.Helper()
+
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: got error %v; want %v", what, err, want)
+	}
+}
+
+func checkStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %q; want %q", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %q; want %q", what, got, want)
+		}
+	}
+}
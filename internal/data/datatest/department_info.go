@@ -0,0 +1,140 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+)
+
+var departmentInfoCases = []testCase{
+	{"Insert and Get", func(t *testing.T, m data.Models) {
+		moduleInfo := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		inserted := insertDepartmentInfo(t, m, "Mathematics", "Jane Doe", moduleInfo.ID)
+		if inserted.ID < 1 || inserted.Version != 1 {
+			t.Fatalf("got ID %d, Version %d", inserted.ID, inserted.Version)
+		}
+
+		departmentInfo, err := m.DepartmentInfos.Get(int64(inserted.ID))
+		checkErr(t, "Get", err, nil)
+		if *departmentInfo != *inserted {
+			t.Fatalf("got %+v; want %+v", departmentInfo, inserted)
+		}
+	}},
+	{"Insert of a missing module gives ErrModuleNotFound", func(t *testing.T, m data.Models) {
+		departmentInfo := &data.DepartmentInfo{DepartmentName: "Mathematics", DepartmentDirector: "Jane Doe", ModuleId: 1000}
+		checkErr(t, "Insert", m.DepartmentInfos.Insert(departmentInfo), data.ErrModuleNotFound)
+	}},
+	{"Get of a missing ID gives ErrRecordNotFound", func(t *testing.T, m data.Models) {
+		_, err := m.DepartmentInfos.Get(0)
+		checkErr(t, "Get(0)", err, data.ErrRecordNotFound)
+		_, err = m.DepartmentInfos.Get(1000)
+		checkErr(t, "Get(1000)", err, data.ErrRecordNotFound)
+	}},
+	{"GetAll filters, sorts and paginates", func(t *testing.T, m data.Models) {
+		algebra := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		databases := insertModuleInfo(t, m, "Databases", 12, "Final")
+		mathematics := insertDepartmentInfo(t, m, "mathematics", "Jane Doe", algebra.ID)
+		physics := insertDepartmentInfo(t, m, "physics", "John Smith", algebra.ID)
+		computing := insertDepartmentInfo(t, m, "computing", "Janet Roe", databases.ID)
+
+		tests := []struct {
+			name     string
+			moduleID int
+			director string
+			filters  data.Filters
+			want     []int64
+			total    int
+		}{
+			{"everything", 0, "", filters(1, 20, "id"), departmentIDs(mathematics, physics, computing), 3},
+			{"module", algebra.ID, "", filters(1, 20, "id"), departmentIDs(mathematics, physics), 2},
+			{"director", 0, "DOE", filters(1, 20, "id"), departmentIDs(mathematics), 1},
+			{"module and director", databases.ID, "jan", filters(1, 20, "id"), departmentIDs(computing), 1},
+			{"sorted by name", 0, "", filters(1, 20, "-department_name"), departmentIDs(physics, mathematics, computing), 3},
+			{"second page", 0, "", filters(2, 2, "id"), departmentIDs(computing), 3},
+			{"past the last page", 0, "", filters(3, 2, "id"), departmentIDs(), 0},
+		}
+		for _, tt := range tests {
+			departmentInfos, metadata, err := m.DepartmentInfos.GetAll(tt.moduleID, tt.director, tt.filters)
+			checkErr(t, tt.name, err, nil)
+			checkIDs(t, tt.name, departmentIDs(departmentInfos...), tt.want)
+			if metadata.TotalRecords != tt.total {
+				t.Fatalf("%s: got %d total records; want %d", tt.name, metadata.TotalRecords, tt.total)
+			}
+		}
+	}},
+	{"Update bumps the version and rejects stale versions", func(t *testing.T, m data.Models) {
+		algebra := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		databases := insertModuleInfo(t, m, "Databases", 12, "Final")
+		inserted := insertDepartmentInfo(t, m, "Mathematics", "Jane Doe", algebra.ID)
+		stale := *inserted
+
+		departmentInfo := *inserted
+		departmentInfo.StaffQuantity = 20
+		departmentInfo.ModuleId = databases.ID
+		checkErr(t, "Update", m.DepartmentInfos.Update(&departmentInfo), nil)
+		if departmentInfo.Version != 2 {
+			t.Fatalf("got version %d; want 2", departmentInfo.Version)
+		}
+
+		stale.StaffQuantity = 30
+		checkErr(t, "Update of a stale version", m.DepartmentInfos.Update(&stale), data.ErrEditConflict)
+
+		got, err := m.DepartmentInfos.Get(int64(inserted.ID))
+		checkErr(t, "Get", err, nil)
+		if *got != departmentInfo {
+			t.Fatalf("got %+v; want %+v", got, departmentInfo)
+		}
+	}},
+	{"Update to a missing module gives ErrModuleNotFound", func(t *testing.T, m data.Models) {
+		moduleInfo := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		departmentInfo := insertDepartmentInfo(t, m, "Mathematics", "Jane Doe", moduleInfo.ID)
+
+		departmentInfo.ModuleId = 1000
+		checkErr(t, "Update", m.DepartmentInfos.Update(departmentInfo), data.ErrModuleNotFound)
+	}},
+	{"Delete", func(t *testing.T, m data.Models) {
+		moduleInfo := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		departmentInfo := insertDepartmentInfo(t, m, "Mathematics", "Jane Doe", moduleInfo.ID)
+
+		checkErr(t, "Delete", m.DepartmentInfos.Delete(int64(departmentInfo.ID)), nil)
+		_, err := m.DepartmentInfos.Get(int64(departmentInfo.ID))
+		checkErr(t, "Get", err, data.ErrRecordNotFound)
+		checkErr(t, "second Delete", m.DepartmentInfos.Delete(int64(departmentInfo.ID)), data.ErrRecordNotFound)
+	}},
+	{"a module referenced by a department info can't be deleted", func(t *testing.T, m data.Models) {
+		moduleInfo := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		departmentInfo := insertDepartmentInfo(t, m, "Mathematics", "Jane Doe", moduleInfo.ID)
+
+		if err := m.ModuleInfos.Delete(int64(moduleInfo.ID)); err == nil {
+			t.Fatal("Delete of the module info: got no error")
+		}
+		_, err := m.ModuleInfos.Get(int64(moduleInfo.ID))
+		checkErr(t, "Get of the module info", err, nil)
+
+		checkErr(t, "Delete of the department info", m.DepartmentInfos.Delete(int64(departmentInfo.ID)), nil)
+		checkErr(t, "Delete of the module info", m.ModuleInfos.Delete(int64(moduleInfo.ID)), nil)
+	}},
+}
+
+func insertDepartmentInfo(t *testing.T, m data.Models, name, director string, moduleID int) *data.DepartmentInfo {
+	t.Helper()
+
+	departmentInfo := &data.DepartmentInfo{
+		DepartmentName:     name,
+		StaffQuantity:      10,
+		DepartmentDirector: director,
+		ModuleId:           moduleID,
+	}
+	err := m.DepartmentInfos.Insert(departmentInfo)
+	if err != nil {
+		t.Fatalf("insert department info %s: %v", name, err)
+	}
+	return departmentInfo
+}
+
+func departmentIDs(departmentInfos ...*data.DepartmentInfo) []int64 {
+	ids := []int64{}
+	for _, departmentInfo := range departmentInfos {
+		ids = append(ids, int64(departmentInfo.ID))
+	}
+	return ids
+}
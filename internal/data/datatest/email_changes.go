@@ -0,0 +1,46 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+	"time"
+)
+
+var emailChangeCases = []testCase{
+	{"GetNewEmail returns the address of the token", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		token, err := m.EmailChanges.New(userInfo.ID, "alice@example.org", time.Hour)
+		checkErr(t, "New", err, nil)
+		if token.Scope != data.ScopeEmailChange || token.UserID != userInfo.ID {
+			t.Fatalf("got %+v; want an email change token of user info %d", token, userInfo.ID)
+		}
+
+		newEmail, err := m.EmailChanges.GetNewEmail(token.Plaintext)
+		checkErr(t, "GetNewEmail", err, nil)
+		if newEmail != "alice@example.org" {
+			t.Fatalf("got %q; want %q", newEmail, "alice@example.org")
+		}
+	}},
+	{"GetNewEmail ignores expired, deleted and unknown tokens", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		expired, err := m.EmailChanges.New(userInfo.ID, "alice@example.org", -time.Hour)
+		checkErr(t, "New", err, nil)
+		deleted, err := m.EmailChanges.New(userInfo.ID, "alice@example.net", time.Hour)
+		checkErr(t, "New", err, nil)
+		checkErr(t, "DeleteAllForUser", m.Tokens.DeleteAllForUser(data.ScopeEmailChange, userInfo.ID), nil)
+
+		_, err = m.EmailChanges.GetNewEmail(expired.Plaintext)
+		checkErr(t, "GetNewEmail of an expired token", err, data.ErrRecordNotFound)
+		_, err = m.EmailChanges.GetNewEmail(deleted.Plaintext)
+		checkErr(t, "GetNewEmail of a deleted token", err, data.ErrRecordNotFound)
+		_, err = m.EmailChanges.GetNewEmail("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		checkErr(t, "GetNewEmail of an unknown token", err, data.ErrRecordNotFound)
+	}},
+	{"GetNewEmail ignores tokens of another scope", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		token := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+
+		_, err := m.EmailChanges.GetNewEmail(token.Plaintext)
+		checkErr(t, "GetNewEmail", err, data.ErrRecordNotFound)
+	}},
+}
@@ -0,0 +1,64 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+	"time"
+)
+
+var loginFailureCases = []testCase{
+	{"Get of an unknown key gives ErrRecordNotFound", func(t *testing.T, m data.Models) {
+		_, err := m.LoginFailures.Get("email:alice@example.com")
+		checkErr(t, "Get", err, data.ErrRecordNotFound)
+	}},
+	{"Record counts the failures within the window", func(t *testing.T, m data.Models) {
+		for want := 1; want <= 3; want++ {
+			failure, err := m.LoginFailures.Record("email:alice@example.com", time.Hour)
+			checkErr(t, "Record", err, nil)
+			if failure.Key != "email:alice@example.com" || failure.Failures != want || failure.LastFailure.IsZero() || !failure.LockedUntil.IsZero() {
+				t.Fatalf("got %+v; want %d failures and no lock", failure, want)
+			}
+		}
+
+		failure, err := m.LoginFailures.Get("email:alice@example.com")
+		checkErr(t, "Get", err, nil)
+		if failure.Failures != 3 {
+			t.Fatalf("got %d failures; want 3", failure.Failures)
+		}
+		_, err = m.LoginFailures.Get("ip:203.0.113.7")
+		checkErr(t, "Get of another key", err, data.ErrRecordNotFound)
+	}},
+	{"Record restarts the count after the window", func(t *testing.T, m data.Models) {
+		_, err := m.LoginFailures.Record("email:alice@example.com", time.Hour)
+		checkErr(t, "Record", err, nil)
+		_, err = m.LoginFailures.Record("email:alice@example.com", time.Hour)
+		checkErr(t, "Record", err, nil)
+
+		// With a negative window, even the last failure is older than the window.
+		failure, err := m.LoginFailures.Record("email:alice@example.com", -time.Hour)
+		checkErr(t, "Record", err, nil)
+		if failure.Failures != 1 {
+			t.Fatalf("got %d failures; want 1", failure.Failures)
+		}
+	}},
+	{"Lock and Reset", func(t *testing.T, m data.Models) {
+		until := time.Now().Add(15 * time.Minute)
+		checkErr(t, "Lock of an unknown key", m.LoginFailures.Lock("email:alice@example.com", until), nil)
+		_, err := m.LoginFailures.Get("email:alice@example.com")
+		checkErr(t, "Get after locking an unknown key", err, data.ErrRecordNotFound)
+
+		_, err = m.LoginFailures.Record("email:alice@example.com", time.Hour)
+		checkErr(t, "Record", err, nil)
+		checkErr(t, "Lock", m.LoginFailures.Lock("email:alice@example.com", until), nil)
+		failure, err := m.LoginFailures.Get("email:alice@example.com")
+		checkErr(t, "Get", err, nil)
+		// The column keeps whole seconds.
+		if !failure.LockedUntil.Equal(until.Round(time.Second)) {
+			t.Fatalf("got LockedUntil %v; want %v", failure.LockedUntil, until.Round(time.Second))
+		}
+
+		checkErr(t, "Reset", m.LoginFailures.Reset("email:alice@example.com"), nil)
+		_, err = m.LoginFailures.Get("email:alice@example.com")
+		checkErr(t, "Get after Reset", err, data.ErrRecordNotFound)
+	}},
+}
@@ -0,0 +1,122 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+)
+
+var mfaCases = []testCase{
+	{"enrollment", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		_, err := m.MFA.Get(userInfo.ID)
+		checkErr(t, "Get before SetSecret", err, data.ErrRecordNotFound)
+		checkErr(t, "Enable before SetSecret", m.MFA.Enable(userInfo.ID, []string{"aaaa-bbbb"}), data.ErrRecordNotFound)
+
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "JBSWY3DPEHPK3PXP"), nil)
+		mfa, err := m.MFA.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if *mfa != (data.MFA{UserID: userInfo.ID, Secret: "JBSWY3DPEHPK3PXP"}) {
+			t.Fatalf("got %+v; want a disabled enrollment", mfa)
+		}
+
+		checkErr(t, "Enable", m.MFA.Enable(userInfo.ID, []string{"aaaa-bbbb"}), nil)
+		useStep(t, m, userInfo.ID, 100, true)
+		mfa, err = m.MFA.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if !mfa.Enabled || mfa.LastUsedStep != 100 {
+			t.Fatalf("got %+v; want MFA enabled at step 100", mfa)
+		}
+
+		// Starting over disables MFA until it is confirmed again.
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "KRUGS4ZANFZSAYJA"), nil)
+		mfa, err = m.MFA.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if *mfa != (data.MFA{UserID: userInfo.ID, Secret: "KRUGS4ZANFZSAYJA"}) {
+			t.Fatalf("got %+v; want a disabled enrollment", mfa)
+		}
+	}},
+	{"SetSecret of a missing user info fails", func(t *testing.T, m data.Models) {
+		if err := m.MFA.SetSecret(1000, "JBSWY3DPEHPK3PXP"); err == nil {
+			t.Fatal("got no error")
+		}
+	}},
+	{"UseStep only accepts later steps", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		useStep(t, m, userInfo.ID, 10, false)
+
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "JBSWY3DPEHPK3PXP"), nil)
+		useStep(t, m, userInfo.ID, 10, true)
+		useStep(t, m, userInfo.ID, 10, false)
+		useStep(t, m, userInfo.ID, 9, false)
+		useStep(t, m, userInfo.ID, 11, true)
+	}},
+	{"recovery codes are single use and replaced by Enable", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "JBSWY3DPEHPK3PXP"), nil)
+		checkErr(t, "Enable", m.MFA.Enable(userInfo.ID, []string{"aaaa-bbbb", "cccc-dddd"}), nil)
+
+		useRecoveryCode(t, m, userInfo.ID, "aaaa-bbbb", true)
+		useRecoveryCode(t, m, userInfo.ID, "aaaa-bbbb", false)
+		useRecoveryCode(t, m, userInfo.ID, "eeee-ffff", false)
+
+		checkErr(t, "Enable", m.MFA.Enable(userInfo.ID, []string{"eeee-ffff"}), nil)
+		useRecoveryCode(t, m, userInfo.ID, "cccc-dddd", false)
+		useRecoveryCode(t, m, userInfo.ID, "eeee-ffff", true)
+	}},
+	{"Disable removes the secret and the recovery codes", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "JBSWY3DPEHPK3PXP"), nil)
+		checkErr(t, "Enable", m.MFA.Enable(userInfo.ID, []string{"aaaa-bbbb"}), nil)
+
+		checkErr(t, "Disable", m.MFA.Disable(userInfo.ID), nil)
+		_, err := m.MFA.Get(userInfo.ID)
+		checkErr(t, "Get", err, data.ErrRecordNotFound)
+		useRecoveryCode(t, m, userInfo.ID, "aaaa-bbbb", false)
+	}},
+	{"MissingForRoles", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "admin"), nil)
+
+		missingForRoles(t, m, userInfo.ID, []string{"admin"}, true)
+		missingForRoles(t, m, userInfo.ID, []string{"user"}, false)
+		missingForRoles(t, m, userInfo.ID, nil, false)
+
+		// An enrollment which isn't confirmed yet doesn't count.
+		checkErr(t, "SetSecret", m.MFA.SetSecret(userInfo.ID, "JBSWY3DPEHPK3PXP"), nil)
+		missingForRoles(t, m, userInfo.ID, []string{"user", "admin"}, true)
+
+		checkErr(t, "Enable", m.MFA.Enable(userInfo.ID, nil), nil)
+		missingForRoles(t, m, userInfo.ID, []string{"user", "admin"}, false)
+	}},
+}
+
+func useStep(t *testing.T, m data.Models, userID, step int64, want bool) {
+	t.Helper()
+
+	ok, err := m.MFA.UseStep(userID, step)
+	checkErr(t, "UseStep", err, nil)
+	if ok != want {
+		t.Fatalf("UseStep(%d) = %v; want %v", step, ok, want)
+	}
+}
+
+func useRecoveryCode(t *testing.T, m data.Models, userID int64, code string, want bool) {
+	t.Helper()
+
+	ok, err := m.MFA.UseRecoveryCode(userID, code)
+	checkErr(t, "UseRecoveryCode", err, nil)
+	if ok != want {
+		t.Fatalf("UseRecoveryCode(%q) = %v; want %v", code, ok, want)
+	}
+}
+
+func missingForRoles(t *testing.T, m data.Models, userID int64, roles []string, want bool) {
+	t.Helper()
+
+	missing, err := m.MFA.MissingForRoles(userID, roles)
+	checkErr(t, "MissingForRoles", err, nil)
+	if missing != want {
+		t.Fatalf("MissingForRoles(%q) = %v; want %v", roles, missing, want)
+	}
+}
@@ -0,0 +1,107 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+	"time"
+)
+
+var moduleInfoCases = []testCase{
+	{"Create and Get", func(t *testing.T, m data.Models) {
+		created := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		if created.ID < 1 || created.CreatedAt.IsZero() || created.Version != "1" {
+			t.Fatalf("got ID %d, CreatedAt %v, Version %q", created.ID, created.CreatedAt, created.Version)
+		}
+
+		moduleInfo, err := m.ModuleInfos.Get(int64(created.ID))
+		checkErr(t, "Get", err, nil)
+		if moduleInfo.ModuleName != "Linear Algebra" || moduleInfo.ModuleDuration != 10 || moduleInfo.ExamType != "Final" ||
+			moduleInfo.Version != "1" || !moduleInfo.CreatedAt.Equal(created.CreatedAt) {
+			t.Fatalf("got %+v; want %+v", moduleInfo, created)
+		}
+	}},
+	{"Create enforces the duration check", func(t *testing.T, m data.Models) {
+		for _, duration := range []int{5, 15} {
+			moduleInfo := &data.ModuleInfo{ModuleName: "Databases", ModuleDuration: time.Duration(duration), ExamType: "Final"}
+			if err := m.ModuleInfos.Create(moduleInfo); err == nil {
+				t.Fatalf("Create with duration %d: got no error", duration)
+			}
+		}
+	}},
+	{"Get of a missing ID gives ErrRecordNotFound", func(t *testing.T, m data.Models) {
+		_, err := m.ModuleInfos.Get(0)
+		checkErr(t, "Get(0)", err, data.ErrRecordNotFound)
+		_, err = m.ModuleInfos.Get(1000)
+		checkErr(t, "Get(1000)", err, data.ErrRecordNotFound)
+	}},
+	{"GetAll filters, sorts and paginates", func(t *testing.T, m data.Models) {
+		linear := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		abstract := insertModuleInfo(t, m, "Abstract Algebra", 8, "Midterm")
+		databases := insertModuleInfo(t, m, "Databases", 12, "Final")
+
+		tests := []struct {
+			name                     string
+			moduleName, examType     string
+			minDuration, maxDuration int
+			filters                  data.Filters
+			want                     []int64
+			total                    int
+		}{
+			{"everything", "", "", 0, 0, filters(1, 20, "id"), moduleIDs(linear, abstract, databases), 3},
+			{"name", "ALGEBRA", "", 0, 0, filters(1, 20, "id"), moduleIDs(linear, abstract), 2},
+			{"every word of the name", "linear algebra", "", 0, 0, filters(1, 20, "id"), moduleIDs(linear), 1},
+			{"exam type", "", "Final", 0, 0, filters(1, 20, "id"), moduleIDs(linear, databases), 2},
+			{"duration", "", "", 9, 11, filters(1, 20, "id"), moduleIDs(linear), 1},
+			{"sorted by duration", "", "", 0, 0, filters(1, 20, "-module_duration"), moduleIDs(databases, linear, abstract), 3},
+			{"sorted by exam type, then id", "", "", 0, 0, filters(1, 20, "exam_type"), moduleIDs(linear, databases, abstract), 3},
+			{"second page", "", "", 0, 0, filters(2, 2, "id"), moduleIDs(databases), 3},
+			{"past the last page", "", "", 0, 0, filters(3, 2, "id"), moduleIDs(), 0},
+		}
+		for _, tt := range tests {
+			moduleInfos, metadata, err := m.ModuleInfos.GetAll(tt.moduleName, tt.examType, tt.minDuration, tt.maxDuration, tt.filters)
+			checkErr(t, tt.name, err, nil)
+			checkIDs(t, tt.name, moduleIDs(moduleInfos...), tt.want)
+			if metadata.TotalRecords != tt.total {
+				t.Fatalf("%s: got %d total records; want %d", tt.name, metadata.TotalRecords, tt.total)
+			}
+		}
+	}},
+	{"Update bumps the version and rejects stale versions", func(t *testing.T, m data.Models) {
+		created := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+		stale := *created
+
+		moduleInfo, err := m.ModuleInfos.Get(int64(created.ID))
+		checkErr(t, "Get", err, nil)
+		moduleInfo.ExamType = "Midterm"
+		checkErr(t, "Update", m.ModuleInfos.Update(moduleInfo), nil)
+		if moduleInfo.Version != "2" || moduleInfo.UpdatedAt.IsZero() {
+			t.Fatalf("got Version %q, UpdatedAt %v; want version 2", moduleInfo.Version, moduleInfo.UpdatedAt)
+		}
+
+		stale.ExamType = "Oral"
+		checkErr(t, "Update of a stale version", m.ModuleInfos.Update(&stale), data.ErrEditConflict)
+
+		moduleInfo, err = m.ModuleInfos.Get(int64(created.ID))
+		checkErr(t, "Get", err, nil)
+		if moduleInfo.ExamType != "Midterm" || moduleInfo.Version != "2" {
+			t.Fatalf("got %+v; want the first update", moduleInfo)
+		}
+	}},
+	{"Delete", func(t *testing.T, m data.Models) {
+		created := insertModuleInfo(t, m, "Linear Algebra", 10, "Final")
+
+		checkErr(t, "Delete", m.ModuleInfos.Delete(int64(created.ID)), nil)
+		_, err := m.ModuleInfos.Get(int64(created.ID))
+		checkErr(t, "Get", err, data.ErrRecordNotFound)
+		checkErr(t, "second Delete", m.ModuleInfos.Delete(int64(created.ID)), data.ErrRecordNotFound)
+		checkErr(t, "Delete(0)", m.ModuleInfos.Delete(0), data.ErrRecordNotFound)
+	}},
+}
+
+func moduleIDs(moduleInfos ...*data.ModuleInfo) []int64 {
+	ids := []int64{}
+	for _, moduleInfo := range moduleInfos {
+		ids = append(ids, int64(moduleInfo.ID))
+	}
+	return ids
+}
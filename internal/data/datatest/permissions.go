@@ -0,0 +1,112 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+)
+
+// migratedPermissions are the codes inserted by the migrations, sorted.
+var migratedPermissions = []string{
+	"department_info:read", "department_info:write",
+	"module_info:read", "module_info:write",
+	"movies:read", "movies:write",
+	"permissions:read", "permissions:write",
+	"roles:read", "roles:write",
+	"user_info:read", "user_info:write",
+}
+
+var permissionCases = []testCase{
+	{"ListAll returns the migrated codes sorted", func(t *testing.T, m data.Models) {
+		permissions, err := m.Permissions.ListAll()
+		checkErr(t, "ListAll", err, nil)
+		checkStrings(t, "ListAll", permissions, migratedPermissions)
+	}},
+	{"Insert", func(t *testing.T, m data.Models) {
+		checkErr(t, "Insert", m.Permissions.Insert("audit:read"), nil)
+		checkErr(t, "Insert of a duplicate", m.Permissions.Insert("audit:read"), data.ErrDuplicatePermission)
+
+		permissions, err := m.Permissions.ListAll()
+		checkErr(t, "ListAll", err, nil)
+		checkStrings(t, "ListAll", permissions, append([]string{"audit:read"}, migratedPermissions...))
+	}},
+	{"granted codes and role codes", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		permissions, err := m.Permissions.GetAllForUserInfo(userInfo.ID)
+		checkErr(t, "GetAllForUserInfo", err, nil)
+		checkStrings(t, "GetAllForUserInfo without grants", permissions, nil)
+
+		// Unknown codes are skipped, and granting a code twice is fine.
+		checkErr(t, "AddForUser", m.Permissions.AddForUser(userInfo.ID, "user_info:read", "unknown:code"), nil)
+		checkErr(t, "AddForUser", m.Permissions.AddForUser(userInfo.ID, "user_info:read", "module_info:read"), nil)
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "user"), nil)
+
+		permissions, err = m.Permissions.GetGrantedForUserInfo(userInfo.ID)
+		checkErr(t, "GetGrantedForUserInfo", err, nil)
+		checkStrings(t, "GetGrantedForUserInfo", permissions, []string{"module_info:read", "user_info:read"})
+		permissions, err = m.Permissions.GetAllForUserInfo(userInfo.ID)
+		checkErr(t, "GetAllForUserInfo", err, nil)
+		checkStrings(t, "GetAllForUserInfo", permissions, []string{"department_info:read", "module_info:read", "user_info:read"})
+
+		// Revoking a code leaves the one the role gives.
+		checkErr(t, "RemoveForUser", m.Permissions.RemoveForUser(userInfo.ID, "user_info:read", "module_info:read"), nil)
+		permissions, err = m.Permissions.GetGrantedForUserInfo(userInfo.ID)
+		checkErr(t, "GetGrantedForUserInfo", err, nil)
+		checkStrings(t, "GetGrantedForUserInfo after RemoveForUser", permissions, nil)
+		permissions, err = m.Permissions.GetAllForUserInfo(userInfo.ID)
+		checkErr(t, "GetAllForUserInfo", err, nil)
+		checkStrings(t, "GetAllForUserInfo after RemoveForUser", permissions, []string{"department_info:read", "module_info:read"})
+	}},
+	{"AddForUser of a missing user info fails", func(t *testing.T, m data.Models) {
+		if err := m.Permissions.AddForUser(1000, "user_info:read"); err == nil {
+			t.Fatal("got no error")
+		}
+	}},
+	{"audited changes", func(t *testing.T, m data.Models) {
+		actor := insertUserInfo(t, m, "admin@example.com", true)
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		checkErr(t, "InsertAudited", m.Permissions.InsertAudited(actor.ID, "audit:read"), nil)
+		checkErr(t, "InsertAudited of a duplicate", m.Permissions.InsertAudited(actor.ID, "audit:read"), data.ErrDuplicatePermission)
+		checkErr(t, "AddForUserAudited", m.Permissions.AddForUserAudited(actor.ID, userInfo.ID, "audit:read"), nil)
+		permissions, err := m.Permissions.GetGrantedForUserInfo(userInfo.ID)
+		checkErr(t, "GetGrantedForUserInfo", err, nil)
+		checkStrings(t, "GetGrantedForUserInfo", permissions, []string{"audit:read"})
+
+		checkErr(t, "RemoveForUserAudited", m.Permissions.RemoveForUserAudited(actor.ID, userInfo.ID, "audit:read"), nil)
+		permissions, err = m.Permissions.GetGrantedForUserInfo(userInfo.ID)
+		checkErr(t, "GetGrantedForUserInfo", err, nil)
+		checkStrings(t, "GetGrantedForUserInfo after RemoveForUserAudited", permissions, nil)
+	}},
+	{"audited changes are undone if the audit entry can't be stored", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		checkErr(t, "AddForUser", m.Permissions.AddForUser(userInfo.ID, "user_info:read"), nil)
+
+		// The actor doesn't exist, so the audit entry breaks its foreign key.
+		if err := m.Permissions.InsertAudited(1000, "audit:read"); err == nil {
+			t.Fatal("InsertAudited: got no error")
+		}
+		if err := m.Permissions.AddForUserAudited(1000, userInfo.ID, "module_info:read"); err == nil {
+			t.Fatal("AddForUserAudited: got no error")
+		}
+		if err := m.Permissions.RemoveForUserAudited(1000, userInfo.ID, "user_info:read"); err == nil {
+			t.Fatal("RemoveForUserAudited: got no error")
+		}
+
+		permissions, err := m.Permissions.ListAll()
+		checkErr(t, "ListAll", err, nil)
+		checkStrings(t, "ListAll", permissions, migratedPermissions)
+		permissions, err = m.Permissions.GetGrantedForUserInfo(userInfo.ID)
+		checkErr(t, "GetGrantedForUserInfo", err, nil)
+		checkStrings(t, "GetGrantedForUserInfo", permissions, []string{"user_info:read"})
+	}},
+	{"deleting the user info deletes its grants", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		checkErr(t, "AddForUser", m.Permissions.AddForUser(userInfo.ID, "user_info:read"), nil)
+		checkErr(t, "Delete", m.UserInfos.Delete(userInfo.ID), nil)
+
+		permissions, err := m.Permissions.GetAllForUserInfo(userInfo.ID)
+		checkErr(t, "GetAllForUserInfo", err, nil)
+		checkStrings(t, "GetAllForUserInfo", permissions, nil)
+	}},
+}
@@ -0,0 +1,122 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"strings"
+	"testing"
+)
+
+var roleCases = []testCase{
+	{"GetAll returns the migrated roles", func(t *testing.T, m data.Models) {
+		roles, err := m.Roles.GetAll()
+		checkErr(t, "GetAll", err, nil)
+		if len(roles) != 2 || roles[0].Name != "user" || roles[1].Name != "admin" {
+			t.Fatalf("got %+v; want the user and admin roles", roles)
+		}
+		checkStrings(t, "permissions of user", roles[0].Permissions, []string{"department_info:read", "module_info:read"})
+		// Admins get every code but movies:*.
+		var admin []string
+		for _, code := range migratedPermissions {
+			if !strings.HasPrefix(code, "movies:") {
+				admin = append(admin, code)
+			}
+		}
+		checkStrings(t, "permissions of admin", roles[1].Permissions, admin)
+	}},
+	{"Insert", func(t *testing.T, m data.Models) {
+		role := &data.Role{Name: "auditor", Permissions: data.Permissions{"roles:read", "permissions:read"}}
+		checkErr(t, "Insert", m.Roles.Insert(role), nil)
+		if role.ID < 1 {
+			t.Fatalf("got ID %d", role.ID)
+		}
+
+		roles, err := m.Roles.GetAll()
+		checkErr(t, "GetAll", err, nil)
+		last := roles[len(roles)-1]
+		if len(roles) != 3 || last.ID != role.ID || last.Name != "auditor" {
+			t.Fatalf("got %+v; want the auditor role last", roles)
+		}
+		checkStrings(t, "permissions of auditor", last.Permissions, []string{"permissions:read", "roles:read"})
+	}},
+	{"Insert rejects duplicate names and unknown codes", func(t *testing.T, m data.Models) {
+		checkErr(t, "Insert of a duplicate", m.Roles.Insert(&data.Role{Name: "admin"}), data.ErrDuplicateRole)
+		checkErr(t, "Insert with an unknown code",
+			m.Roles.Insert(&data.Role{Name: "auditor", Permissions: data.Permissions{"roles:read", "unknown:code"}}),
+			data.ErrUnknownPermission)
+
+		roles, err := m.Roles.GetAll()
+		checkErr(t, "GetAll", err, nil)
+		if len(roles) != 2 {
+			t.Fatalf("got %+v; want only the migrated roles", roles)
+		}
+	}},
+	{"SetForUser and GetAllForUser", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		names, err := m.Roles.GetAllForUser(userInfo.ID)
+		checkErr(t, "GetAllForUser", err, nil)
+		checkStrings(t, "GetAllForUser without roles", names, nil)
+
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "user", "admin"), nil)
+		names, err = m.Roles.GetAllForUser(userInfo.ID)
+		checkErr(t, "GetAllForUser", err, nil)
+		checkStrings(t, "GetAllForUser", names, []string{"admin", "user"})
+
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "user"), nil)
+		names, err = m.Roles.GetAllForUser(userInfo.ID)
+		checkErr(t, "GetAllForUser", err, nil)
+		checkStrings(t, "GetAllForUser after replacing", names, []string{"user"})
+
+		// An unknown name leaves the roles as they were.
+		checkErr(t, "SetForUser with an unknown name", m.Roles.SetForUser(userInfo.ID, "admin", "unknown"), data.ErrRecordNotFound)
+		names, err = m.Roles.GetAllForUser(userInfo.ID)
+		checkErr(t, "GetAllForUser", err, nil)
+		checkStrings(t, "GetAllForUser after a failed SetForUser", names, []string{"user"})
+	}},
+	{"SetForUser makes the first role the Role of the user info", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "admin", "user"), nil)
+		got, err := m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if got.Role != "admin" {
+			t.Fatalf("got role %q; want %q", got.Role, "admin")
+		}
+		userInfos, _, err := m.UserInfos.GetAll("", "admin", nil, filters(1, 20, "id"))
+		checkErr(t, "GetAll", err, nil)
+		if len(userInfos) != 1 || userInfos[0].ID != userInfo.ID {
+			t.Fatalf("got %+v; want alice as the only admin", userInfos)
+		}
+
+		// A failed SetForUser leaves the role as it was.
+		checkErr(t, "SetForUser with an unknown name", m.Roles.SetForUser(userInfo.ID, "user", "unknown"), data.ErrRecordNotFound)
+		got, err = m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if got.Role != "admin" {
+			t.Fatalf("got role %q after a failed SetForUser; want %q", got.Role, "admin")
+		}
+
+		checkErr(t, "SetForUser", m.Roles.SetForUser(userInfo.ID, "user"), nil)
+		got, err = m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if got.Role != "user" {
+			t.Fatalf("got role %q; want %q", got.Role, "user")
+		}
+	}},
+}
+
+var permissionAuditCases = []testCase{
+	{"Insert sets the ID and CreatedAt", func(t *testing.T, m data.Models) {
+		actor := insertUserInfo(t, m, "admin@example.com", true)
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+
+		created := &data.PermissionAuditEntry{ActorID: actor.ID, Action: data.AuditActionCreate, Code: "audit:read"}
+		checkErr(t, "Insert", m.PermissionAudit.Insert(created), nil)
+		granted := &data.PermissionAuditEntry{ActorID: actor.ID, Action: data.AuditActionGrant, Code: "audit:read", UserInfoID: &userInfo.ID}
+		checkErr(t, "Insert", m.PermissionAudit.Insert(granted), nil)
+
+		if created.ID < 1 || created.CreatedAt.IsZero() || granted.ID <= created.ID {
+			t.Fatalf("got %+v and %+v; want increasing IDs", created, granted)
+		}
+	}},
+}
@@ -0,0 +1,221 @@
+package datatest
+
+import (
+	"bytes"
+	"errors"
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+	"time"
+)
+
+var tokenCases = []testCase{
+	{"New for a missing user info fails", func(t *testing.T, m data.Models) {
+		if _, err := m.Tokens.New(1000, time.Hour, data.ScopeAuthentication); err == nil {
+			t.Fatal("got no error")
+		}
+	}},
+	{"DeleteAllForUser only deletes the scope", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		authentication := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+		activation := newToken(t, m, userInfo.ID, time.Hour, data.ScopeActivation)
+
+		checkErr(t, "DeleteAllForUser", m.Tokens.DeleteAllForUser(data.ScopeAuthentication, userInfo.ID), nil)
+		_, err := m.UserInfos.GetForToken(data.ScopeAuthentication, authentication.Plaintext)
+		checkErr(t, "GetForToken of the deleted token", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeActivation, activation.Plaintext)
+		checkErr(t, "GetForToken of the other scope", err, nil)
+	}},
+	{"DeleteForToken", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		token := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+
+		checkErr(t, "DeleteForToken of another scope", m.Tokens.DeleteForToken(data.ScopeActivation, token.Plaintext), data.ErrRecordNotFound)
+		checkErr(t, "DeleteForToken", m.Tokens.DeleteForToken(data.ScopeAuthentication, token.Plaintext), nil)
+		checkErr(t, "second DeleteForToken", m.Tokens.DeleteForToken(data.ScopeAuthentication, token.Plaintext), data.ErrRecordNotFound)
+	}},
+	{"DeleteFamilyForToken revokes the family only", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		access, refresh := newPair(t, m, userInfo.ID, time.Hour)
+		other := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+
+		checkErr(t, "DeleteFamilyForToken", m.Tokens.DeleteFamilyForToken(data.ScopeAuthentication, access.Plaintext), nil)
+		_, err := m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the refresh token", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, other.Plaintext)
+		checkErr(t, "GetForToken of a token without family", err, nil)
+
+		// A token without a family only takes itself along.
+		checkErr(t, "DeleteFamilyForToken without family", m.Tokens.DeleteFamilyForToken(data.ScopeAuthentication, other.Plaintext), nil)
+		checkErr(t, "second DeleteFamilyForToken", m.Tokens.DeleteFamilyForToken(data.ScopeAuthentication, other.Plaintext), data.ErrRecordNotFound)
+	}},
+	{"DeleteFamily", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, refresh := newPair(t, m, userInfo.ID, time.Hour)
+		_, other := newPair(t, m, userInfo.ID, time.Hour)
+
+		checkErr(t, "DeleteFamily", m.Tokens.DeleteFamily(refresh.Family), nil)
+		_, err := m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the deleted family", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, other.Plaintext)
+		checkErr(t, "GetForToken of another family", err, nil)
+	}},
+	{"NewPair stores both tokens in a new family", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		access, refresh := newPair(t, m, userInfo.ID, time.Hour)
+		if !bytes.Equal(refresh.Family, refresh.Hash) || !bytes.Equal(access.Family, refresh.Family) {
+			t.Fatal("NewPair didn't start a family named after the refresh token")
+		}
+		_, err := m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the refresh token", err, nil)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, access.Plaintext)
+		checkErr(t, "GetForToken of the access token", err, nil)
+
+		// An access token without a hash, like a signed JWT, isn't stored.
+		signed, refresh, err := m.Tokens.NewPair(userInfo.ID, time.Hour, func(refresh *data.Token) (*data.Token, error) {
+			return &data.Token{Plaintext: "signed", UserID: refresh.UserID, Family: refresh.Family}, nil
+		})
+		checkErr(t, "NewPair with a signed access token", err, nil)
+		if signed.Plaintext != "signed" {
+			t.Fatalf("got access token %+v; want the one issued", signed)
+		}
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the refresh token", err, nil)
+	}},
+	{"NewPair stores nothing if the access token can't be issued", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		var refresh *data.Token
+		_, _, err := m.Tokens.NewPair(userInfo.ID, time.Hour, func(token *data.Token) (*data.Token, error) {
+			refresh = token
+			return nil, errIssue
+		})
+		checkErr(t, "NewPair", err, errIssue)
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the refresh token", err, data.ErrRecordNotFound)
+
+		// Neither is stored if one of them can't be.
+		_, _, err = m.Tokens.NewPair(userInfo.ID, time.Hour, func(token *data.Token) (*data.Token, error) {
+			refresh = token
+			access, err := storedAccess(token)
+			if err == nil {
+				access.UserID = 1000
+			}
+			return access, err
+		})
+		if err == nil {
+			t.Fatal("NewPair with an access token of a missing user info: got no error")
+		}
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, refresh.Plaintext)
+		checkErr(t, "GetForToken of the refresh token", err, data.ErrRecordNotFound)
+	}},
+	{"Rotate keeps the family and detects reuse", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, first := newPair(t, m, userInfo.ID, time.Hour)
+
+		access, second, err := m.Tokens.Rotate(first.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate", err, nil)
+		if second.UserID != userInfo.ID || second.Scope != data.ScopeRefresh || !bytes.Equal(second.Family, first.Family) {
+			t.Fatalf("got %+v; want a refresh token of the same family", second)
+		}
+		if !bytes.Equal(access.Family, first.Family) {
+			t.Fatalf("got %+v; want an access token of the same family", access)
+		}
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, second.Plaintext)
+		checkErr(t, "GetForToken of the new refresh token", err, nil)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, access.Plaintext)
+		checkErr(t, "GetForToken of the new access token", err, nil)
+
+		_, _, err = m.Tokens.Rotate(first.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate of a used token", err, data.ErrTokenReused)
+		_, _, err = m.Tokens.Rotate(second.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate after the reuse", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, access.Plaintext)
+		checkErr(t, "GetForToken of the access token after the reuse", err, data.ErrRecordNotFound)
+	}},
+	{"Rotate leaves the token unused if the access token can't be issued", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, refresh := newPair(t, m, userInfo.ID, time.Hour)
+
+		var failed *data.Token
+		_, _, err := m.Tokens.Rotate(refresh.Plaintext, time.Hour, func(token *data.Token) (*data.Token, error) {
+			failed = token
+			return nil, errIssue
+		})
+		checkErr(t, "Rotate", err, errIssue)
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, failed.Plaintext)
+		checkErr(t, "GetForToken of the refresh token which wasn't issued", err, data.ErrRecordNotFound)
+
+		_, _, err = m.Tokens.Rotate(refresh.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate after the failure", err, nil)
+	}},
+	{"Rotate lets the issuer read the store", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, refresh := newPair(t, m, userInfo.ID, time.Hour)
+
+		_, _, err := m.Tokens.Rotate(refresh.Plaintext, time.Hour, func(token *data.Token) (*data.Token, error) {
+			_, err := m.UserInfos.Get(token.UserID)
+			if err != nil {
+				return nil, err
+			}
+			_, err = m.Permissions.GetAllForUserInfo(token.UserID)
+			if err != nil {
+				return nil, err
+			}
+			return storedAccess(token)
+		})
+		checkErr(t, "Rotate", err, nil)
+	}},
+	{"Rotate detects an exchange while the access token is issued", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, refresh := newPair(t, m, userInfo.ID, time.Hour)
+
+		var concurrent *data.Token
+		_, _, err := m.Tokens.Rotate(refresh.Plaintext, time.Hour, func(token *data.Token) (*data.Token, error) {
+			var err error
+			_, concurrent, err = m.Tokens.Rotate(refresh.Plaintext, time.Hour, storedAccess)
+			if err != nil {
+				return nil, err
+			}
+			return storedAccess(token)
+		})
+		checkErr(t, "Rotate", err, data.ErrTokenReused)
+		_, err = m.UserInfos.GetForToken(data.ScopeRefresh, concurrent.Plaintext)
+		checkErr(t, "GetForToken of the concurrently issued refresh token", err, data.ErrRecordNotFound)
+	}},
+	{"Rotate of an unknown or expired token gives ErrRecordNotFound", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		_, expired := newPair(t, m, userInfo.ID, -time.Hour)
+		access := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+
+		_, _, err := m.Tokens.Rotate(expired.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate of an expired token", err, data.ErrRecordNotFound)
+		_, _, err = m.Tokens.Rotate(access.Plaintext, time.Hour, storedAccess)
+		checkErr(t, "Rotate of an access token", err, data.ErrRecordNotFound)
+		_, _, err = m.Tokens.Rotate("ABCDEFGHIJKLMNOPQRSTUVWXYZ", time.Hour, storedAccess)
+		checkErr(t, "Rotate of an unknown token", err, data.ErrRecordNotFound)
+	}},
+}
+
+// errIssue is returned by the access token issuers which fail on purpose.
+var errIssue = errors.New("access token not issued")
+
+// storedAccess issues the access token of a pair the way the API does in database
+// mode.
+func storedAccess(refresh *data.Token) (*data.Token, error) {
+	access, err := data.GenerateToken(refresh.UserID, time.Hour, data.ScopeAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	access.Family = refresh.Family
+	return access, nil
+}
+
+// newPair issues a refresh token with the ttl together with its stored access token.
+func newPair(t *testing.T, m data.Models, userID int64, ttl time.Duration) (*data.Token, *data.Token) {
+	t.Helper()
+
+	access, refresh, err := m.Tokens.NewPair(userID, ttl, storedAccess)
+	if err != nil {
+		t.Fatalf("new token pair: %v", err)
+	}
+	return access, refresh
+}
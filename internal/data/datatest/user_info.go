@@ -0,0 +1,242 @@
+package datatest
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"testing"
+	"time"
+)
+
+var userInfoCases = []testCase{
+	{"Insert sets the ID, CreatedAt and Version", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", false)
+		if userInfo.ID < 1 || userInfo.CreatedAt.IsZero() || userInfo.Version != 1 {
+			t.Fatalf("got ID %d, CreatedAt %v, Version %d", userInfo.ID, userInfo.CreatedAt, userInfo.Version)
+		}
+	}},
+	{"Insert rejects an email which differs only in case", func(t *testing.T, m data.Models) {
+		insertUserInfo(t, m, "alice@example.com", false)
+
+		userInfo := withPassword(t, &data.UserInfo{Name: "Alice", Email: "ALICE@example.com", Role: "user"})
+		checkErr(t, "Insert", m.UserInfos.Insert(userInfo), data.ErrDuplicateEmail)
+	}},
+	{"Insert with roles", func(t *testing.T, m data.Models) {
+		userInfo := withPassword(t, &data.UserInfo{Name: "Alice", Email: "alice@example.com"})
+		checkErr(t, "Insert", m.UserInfos.Insert(userInfo, "admin", "user"), nil)
+
+		names, err := m.Roles.GetAllForUser(userInfo.ID)
+		checkErr(t, "GetAllForUser", err, nil)
+		checkStrings(t, "GetAllForUser", names, []string{"admin", "user"})
+		got, err := m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if userInfo.Role != "admin" || got.Role != "admin" {
+			t.Fatalf("got role %q, stored %q; want %q", userInfo.Role, got.Role, "admin")
+		}
+	}},
+	{"Insert with an unknown role stores nothing", func(t *testing.T, m data.Models) {
+		userInfo := withPassword(t, &data.UserInfo{Name: "Alice", Email: "alice@example.com"})
+		checkErr(t, "Insert", m.UserInfos.Insert(userInfo, "user", "unknown"), data.ErrRecordNotFound)
+
+		_, err := m.UserInfos.GetByEmail("alice@example.com")
+		checkErr(t, "GetByEmail", err, data.ErrRecordNotFound)
+		// The email is still free.
+		insertUserInfo(t, m, "alice@example.com", true)
+	}},
+	{"Get returns every column", func(t *testing.T, m data.Models) {
+		inserted := withPassword(t, &data.UserInfo{Name: "Alice", Surname: "Smith", Email: "alice@example.com", Role: "admin", Activated: true})
+		checkErr(t, "Insert", m.UserInfos.Insert(inserted), nil)
+
+		userInfo, err := m.UserInfos.Get(inserted.ID)
+		checkErr(t, "Get", err, nil)
+		if userInfo.Name != "Alice" || userInfo.Surname != "Smith" || userInfo.Email != "alice@example.com" ||
+			userInfo.Role != "admin" || !userInfo.Activated || userInfo.Version != 1 ||
+			!userInfo.CreatedAt.Equal(inserted.CreatedAt) {
+			t.Fatalf("got %+v; want %+v", userInfo, inserted)
+		}
+		match, _, err := userInfo.PasswordHashed.Matches(testPassword)
+		if err != nil || !match {
+			t.Fatalf("Matches() = %v, %v; want true, nil", match, err)
+		}
+	}},
+	{"Get of a missing ID gives ErrRecordNotFound", func(t *testing.T, m data.Models) {
+		_, err := m.UserInfos.Get(0)
+		checkErr(t, "Get(0)", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.Get(1000)
+		checkErr(t, "Get(1000)", err, data.ErrRecordNotFound)
+	}},
+	{"GetByEmail ignores case", func(t *testing.T, m data.Models) {
+		inserted := insertUserInfo(t, m, "alice@example.com", false)
+
+		userInfo, err := m.UserInfos.GetByEmail("Alice@Example.com")
+		checkErr(t, "GetByEmail", err, nil)
+		if userInfo.ID != inserted.ID {
+			t.Fatalf("got ID %d; want %d", userInfo.ID, inserted.ID)
+		}
+		_, err = m.UserInfos.GetByEmail("bob@example.com")
+		checkErr(t, "GetByEmail of a missing email", err, data.ErrRecordNotFound)
+	}},
+	{"GetForToken returns the same record as Get", func(t *testing.T, m data.Models) {
+		inserted := withPassword(t, &data.UserInfo{Name: "Alice", Surname: "Smith", Email: "alice@example.com", Role: "admin", Activated: true})
+		checkErr(t, "Insert", m.UserInfos.Insert(inserted), nil)
+		token := newToken(t, m, inserted.ID, time.Hour, data.ScopeAuthentication)
+
+		want, err := m.UserInfos.Get(inserted.ID)
+		checkErr(t, "Get", err, nil)
+		got, err := m.UserInfos.GetForToken(data.ScopeAuthentication, token.Plaintext)
+		checkErr(t, "GetForToken", err, nil)
+		checkSameUserInfo(t, got, want)
+	}},
+	{"GetForToken ignores expired tokens and other scopes", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		expired := newToken(t, m, userInfo.ID, -time.Hour, data.ScopeAuthentication)
+		activation := newToken(t, m, userInfo.ID, time.Hour, data.ScopeActivation)
+
+		_, err := m.UserInfos.GetForToken(data.ScopeAuthentication, expired.Plaintext)
+		checkErr(t, "GetForToken of an expired token", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, activation.Plaintext)
+		checkErr(t, "GetForToken of another scope", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		checkErr(t, "GetForToken of an unknown token", err, data.ErrRecordNotFound)
+	}},
+	{"GetAll filters, sorts and paginates", func(t *testing.T, m data.Models) {
+		alice := insertUserInfo(t, m, "alice@example.com", true)
+		bob := insertUserInfo(t, m, "bob@example.com", false)
+		carol := insertUserInfo(t, m, "carol@example.com", false)
+		dave := withPassword(t, &data.UserInfo{Name: "Dave", Email: "dave@example.com", Role: "admin", Activated: true})
+		checkErr(t, "Insert", m.UserInfos.Insert(dave), nil)
+
+		notActivated := false
+		tests := []struct {
+			name      string
+			search    string
+			role      string
+			activated *bool
+			filters   data.Filters
+			want      []int64
+			total     int
+		}{
+			{"everything", "", "", nil, filters(1, 20, "id"), []int64{alice.ID, bob.ID, carol.ID, dave.ID}, 4},
+			{"search", "BO", "", nil, filters(1, 20, "id"), []int64{bob.ID}, 1},
+			{"role", "", "admin", nil, filters(1, 20, "id"), []int64{dave.ID}, 1},
+			{"activated", "", "", &notActivated, filters(1, 20, "-id"), []int64{carol.ID, bob.ID}, 2},
+			{"second page", "", "", nil, filters(2, 3, "-id"), []int64{alice.ID}, 4},
+			{"past the last page", "", "", nil, filters(3, 3, "id"), []int64{}, 0},
+		}
+		for _, tt := range tests {
+			userInfos, metadata, err := m.UserInfos.GetAll(tt.search, tt.role, tt.activated, tt.filters)
+			checkErr(t, tt.name, err, nil)
+			var got []int64
+			for _, userInfo := range userInfos {
+				got = append(got, userInfo.ID)
+			}
+			checkIDs(t, tt.name, got, tt.want)
+			if metadata.TotalRecords != tt.total {
+				t.Fatalf("%s: got %d total records; want %d", tt.name, metadata.TotalRecords, tt.total)
+			}
+		}
+	}},
+	{"Update bumps the version and rejects stale versions", func(t *testing.T, m data.Models) {
+		inserted := insertUserInfo(t, m, "alice@example.com", false)
+		stale, err := m.UserInfos.Get(inserted.ID)
+		checkErr(t, "Get", err, nil)
+
+		userInfo, err := m.UserInfos.Get(inserted.ID)
+		checkErr(t, "Get", err, nil)
+		userInfo.Name = "Alicia"
+		userInfo.Activated = true
+		checkErr(t, "Update", m.UserInfos.Update(userInfo), nil)
+		if userInfo.Version != 2 {
+			t.Fatalf("got version %d; want 2", userInfo.Version)
+		}
+
+		stale.Name = "Ally"
+		checkErr(t, "Update of a stale version", m.UserInfos.Update(stale), data.ErrEditConflict)
+
+		userInfo, err = m.UserInfos.Get(inserted.ID)
+		checkErr(t, "Get", err, nil)
+		if userInfo.Name != "Alicia" || !userInfo.Activated || userInfo.Version != 2 {
+			t.Fatalf("got %+v; want the first update", userInfo)
+		}
+	}},
+	{"Update leaves the role alone", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", false)
+		userInfo.Role = "admin"
+		checkErr(t, "Update", m.UserInfos.Update(userInfo), nil)
+
+		userInfo, err := m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, nil)
+		if userInfo.Role != "user" {
+			t.Fatalf("got role %q; want %q", userInfo.Role, "user")
+		}
+	}},
+	{"Update rejects an email taken by another user info", func(t *testing.T, m data.Models) {
+		insertUserInfo(t, m, "alice@example.com", false)
+		bob := insertUserInfo(t, m, "bob@example.com", false)
+
+		bob.Email = "Alice@example.com"
+		checkErr(t, "Update", m.UserInfos.Update(bob), data.ErrDuplicateEmail)
+	}},
+	{"Delete removes the user info and its tokens", func(t *testing.T, m data.Models) {
+		userInfo := insertUserInfo(t, m, "alice@example.com", true)
+		token := newToken(t, m, userInfo.ID, time.Hour, data.ScopeAuthentication)
+
+		checkErr(t, "Delete", m.UserInfos.Delete(userInfo.ID), nil)
+		_, err := m.UserInfos.Get(userInfo.ID)
+		checkErr(t, "Get", err, data.ErrRecordNotFound)
+		_, err = m.UserInfos.GetForToken(data.ScopeAuthentication, token.Plaintext)
+		checkErr(t, "GetForToken", err, data.ErrRecordNotFound)
+		checkErr(t, "second Delete", m.UserInfos.Delete(userInfo.ID), data.ErrRecordNotFound)
+		checkErr(t, "Delete(0)", m.UserInfos.Delete(0), data.ErrRecordNotFound)
+	}},
+	{"FindNotActivatedAndExpired and DeleteExpiredToken", func(t *testing.T, m data.Models) {
+		expired := insertUserInfo(t, m, "alice@example.com", false)
+		newToken(t, m, expired.ID, -time.Hour, data.ScopeActivation)
+		activated := insertUserInfo(t, m, "bob@example.com", true)
+		newToken(t, m, activated.ID, -time.Hour, data.ScopeActivation)
+		pending := insertUserInfo(t, m, "carol@example.com", false)
+		newToken(t, m, pending.ID, time.Hour, data.ScopeActivation)
+
+		userInfos, err := m.UserInfos.FindNotActivatedAndExpired()
+		checkErr(t, "FindNotActivatedAndExpired", err, nil)
+		if len(userInfos) != 1 || userInfos[0].ID != expired.ID || userInfos[0].Email != "alice@example.com" {
+			t.Fatalf("got %+v; want only user info %d", userInfos, expired.ID)
+		}
+
+		checkErr(t, "DeleteExpiredToken", m.UserInfos.DeleteExpiredToken(expired.ID), nil)
+		userInfos, err = m.UserInfos.FindNotActivatedAndExpired()
+		checkErr(t, "FindNotActivatedAndExpired", err, nil)
+		if len(userInfos) != 0 {
+			t.Fatalf("got %+v after DeleteExpiredToken; want none", userInfos)
+		}
+	}},
+}
+
+func filters(page, pageSize int, sort string) data.Filters {
+	return data.Filters{Page: page, PageSize: pageSize, Sort: sort, SortSafelist: []string{sort}}
+}
+
+func checkSameUserInfo(t *testing.T, got, want *data.UserInfo) {
+	t.Helper()
+
+	if got.ID != want.ID || got.Name != want.Name || got.Surname != want.Surname || got.Email != want.Email ||
+		got.Role != want.Role || got.Activated != want.Activated || got.Version != want.Version ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Fatalf("got %+v; want %+v", got, want)
+	}
+	match, _, err := got.PasswordHashed.Matches(testPassword)
+	if err != nil || !match {
+		t.Fatalf("Matches() = %v, %v; want true, nil", match, err)
+	}
+}
+
+func checkIDs(t *testing.T, what string, got, want []int64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got IDs %v; want %v", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got IDs %v; want %v", what, got, want)
+		}
+	}
+}
@@ -1,24 +1,51 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"fmt"
+	"github.com/bxiit/greenlight/internal/validator"
+	"github.com/lib/pq"
+	"time"
 )
 
+// ErrModuleNotFound is returned when a department info references a module_info row
+// which doesn't exist.
+var ErrModuleNotFound = errors.New("referenced module not found")
+
+type DepartmentInfoRepository interface {
+	Insert(departmentInfo *DepartmentInfo) error
+	Get(id int64) (*DepartmentInfo, error)
+	GetAll(moduleID int, director string, filters Filters) ([]*DepartmentInfo, Metadata, error)
+	Update(departmentInfo *DepartmentInfo) error
+	Delete(id int64) error
+}
+
 type DepartmentInfoModel struct {
 	DB *sql.DB
 }
 
+// DepartmentInfoSortSafelist holds the values accepted by the sort parameter when
+// listing department infos.
+var DepartmentInfoSortSafelist = []string{
+	"id", "department_name", "staff_quantity", "department_director",
+	"-id", "-department_name", "-staff_quantity", "-department_director",
+}
+
 func (m DepartmentInfoModel) Insert(departmentInfo *DepartmentInfo) error {
 	query := `INSERT INTO department_info(department_name, staff_quantity, department_director, module_id)
 			VALUES ($1, $2, $3, $4)
-			RETURNING id`
-
-	log.Println("inserting to database")
+			RETURNING id, version`
 
 	args := []interface{}{departmentInfo.DepartmentName, departmentInfo.StaffQuantity, departmentInfo.DepartmentDirector, departmentInfo.ModuleId}
-	return m.DB.QueryRow(query, args...).Scan(&departmentInfo.ID)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&departmentInfo.ID, &departmentInfo.Version)
+	if err != nil {
+		return departmentInfoError(err)
+	}
+	return nil
 }
 
 func (m DepartmentInfoModel) Get(id int64) (*DepartmentInfo, error) {
@@ -27,7 +54,8 @@ func (m DepartmentInfoModel) Get(id int64) (*DepartmentInfo, error) {
 	}
 
 	query := `
-			SELECT * FROM department_info
+			SELECT id, department_name, staff_quantity, department_director, module_id, version
+			FROM department_info
 			WHERE id = $1`
 	var departmentInfo DepartmentInfo
 
@@ -37,6 +65,7 @@ func (m DepartmentInfoModel) Get(id int64) (*DepartmentInfo, error) {
 		&departmentInfo.StaffQuantity,
 		&departmentInfo.DepartmentDirector,
 		&departmentInfo.ModuleId,
+		&departmentInfo.Version,
 	)
 
 	if err != nil {
@@ -50,3 +79,154 @@ func (m DepartmentInfoModel) Get(id int64) (*DepartmentInfo, error) {
 
 	return &departmentInfo, nil
 }
+
+// GetAll returns a page of department infos. A zero moduleID or an empty director
+// means that filter isn't applied; the director is matched as a case-insensitive
+// substring.
+func (m DepartmentInfoModel) GetAll(moduleID int, director string, filters Filters) ([]*DepartmentInfo, Metadata, error) {
+	query := fmt.Sprintf(`
+			SELECT count(*) OVER(), id, department_name, staff_quantity, department_director, module_id, version
+			FROM department_info
+			WHERE (module_id = $1 OR $1 = 0)
+			AND (department_director ILIKE '%%' || $2 || '%%' OR $2 = '')
+			ORDER BY %s %s, id ASC
+			LIMIT $3 OFFSET $4`, filters.sortColumn(nil), filters.sortDirection())
+
+	args := []interface{}{moduleID, director, filters.limit(), filters.offset()}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	departmentInfos := []*DepartmentInfo{}
+	for rows.Next() {
+		departmentInfo := &DepartmentInfo{}
+		err = rows.Scan(
+			&totalRecords,
+			&departmentInfo.ID,
+			&departmentInfo.DepartmentName,
+			&departmentInfo.StaffQuantity,
+			&departmentInfo.DepartmentDirector,
+			&departmentInfo.ModuleId,
+			&departmentInfo.Version,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		departmentInfos = append(departmentInfos, departmentInfo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	return departmentInfos, metadata, nil
+}
+
+// Update saves the department info as long as it still has the version that was
+// read, otherwise ErrEditConflict is returned.
+func (m DepartmentInfoModel) Update(departmentInfo *DepartmentInfo) error {
+	query := `
+			UPDATE department_info
+			SET department_name = $1,
+			    staff_quantity = $2,
+			    department_director = $3,
+			    module_id = $4,
+			    version = version + 1
+			WHERE id = $5 AND version = $6
+			RETURNING version`
+
+	args := []interface{}{
+		departmentInfo.DepartmentName,
+		departmentInfo.StaffQuantity,
+		departmentInfo.DepartmentDirector,
+		departmentInfo.ModuleId,
+		departmentInfo.ID,
+		departmentInfo.Version,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&departmentInfo.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return departmentInfoError(err)
+		}
+	}
+	return nil
+}
+
+func (m DepartmentInfoModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+		DELETE FROM department_info
+		WHERE id = $1`
+
+	result, err := m.DB.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// departmentInfoError translates a violation of the module_id foreign key into
+// ErrModuleNotFound.
+func departmentInfoError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrModuleNotFound
+	}
+	return err
+}
+
+func ValidateDepartmentInfo(v *validator.Validator, departmentInfo *DepartmentInfo) {
+	ValidateDepartmentName(v, departmentInfo.DepartmentName)
+	ValidateStaffQuantity(v, departmentInfo.StaffQuantity)
+	ValidateDepartmentDirector(v, departmentInfo.DepartmentDirector)
+	ValidateDepartmentModuleId(v, departmentInfo.ModuleId)
+}
+
+func ValidateDepartmentName(v *validator.Validator, departmentName string) {
+	v.Check(departmentName != "", "departmentName", "must be provided")
+	v.Check(len(departmentName) <= 255, "departmentName", "must not be more than 255 bytes long")
+}
+
+func ValidateStaffQuantity(v *validator.Validator, staffQuantity int) {
+	v.Check(staffQuantity >= 0, "staffQuantity", "must not be negative")
+}
+
+func ValidateDepartmentDirector(v *validator.Validator, departmentDirector string) {
+	v.Check(departmentDirector != "", "departmentDirector", "must be provided")
+	v.Check(len(departmentDirector) <= 255, "departmentDirector", "must not be more than 255 bytes long")
+}
+
+func ValidateDepartmentModuleId(v *validator.Validator, moduleId int) {
+	v.Check(moduleId > 0, "moduleId", "must be a positive integer")
+}
+
+func ValidateDepartmentInfoFilters(v *validator.Validator, moduleID int, filters Filters) {
+	v.Check(moduleID >= 0, "module_id", "must not be negative")
+	ValidateFilters(v, filters)
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type EmailChangeRepository interface {
+	New(userID int64, newEmail string, ttl time.Duration) (*Token, error)
+	GetNewEmail(tokenPlaintext string) (string, error)
+}
+
+// EmailChangeRepo stores the address a user info asked to switch to, next to the
+// ScopeEmailChange token that was sent to that address.
+type EmailChangeRepo struct {
+	DB *sql.DB
+}
+
+// New() creates an email change token for the user info and remembers the new email
+// address it belongs to. Both rows are written in one transaction.
+func (m EmailChangeRepo) New(userID int64, newEmail string, ttl time.Duration) (*Token, error) {
+	token, err := GenerateToken(userID, ttl, ScopeEmailChange)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_info_tokens (hash, user_info_id, expiry, scope)
+			VALUES ($1, $2, $3, $4)`, token.Hash, token.UserID, token.Expiry, token.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_info_email_changes (hash, new_email)
+			VALUES ($1, $2)`, token.Hash, newEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, tx.Commit()
+}
+
+// GetNewEmail() returns the email address requested together with an unexpired email
+// change token.
+func (m EmailChangeRepo) GetNewEmail(tokenPlaintext string) (string, error) {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+	query := `
+			SELECT user_info_email_changes.new_email
+			FROM user_info_email_changes
+			INNER JOIN user_info_tokens
+			ON user_info_tokens.hash = user_info_email_changes.hash
+			WHERE user_info_tokens.hash = $1
+			AND user_info_tokens.scope = $2
+			AND user_info_tokens.expiry > $3`
+
+	var newEmail string
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, tokenHash[:], ScopeEmailChange, time.Now()).Scan(&newEmail)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", ErrRecordNotFound
+		default:
+			return "", err
+		}
+	}
+	return newEmail, nil
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"github.com/bxiit/greenlight/internal/validator"
+	"math"
+	"strings"
+)
+
+// Filters holds the pagination and sorting query string parameters shared by the
+// listing endpoints.
+type Filters struct {
+	Page         int
+	PageSize     int
+	Sort         string
+	SortSafelist []string
+}
+
+func ValidateFilters(v *validator.Validator, f Filters) {
+	// Check that the page and page_size parameters contain sensible values.
+	v.Check(f.Page > 0, "page", "must be greater than zero")
+	v.Check(f.Page <= 10_000_000, "page", "must be a maximum of 10 million")
+	v.Check(f.PageSize > 0, "page_size", "must be greater than zero")
+	v.Check(f.PageSize <= 100, "page_size", "must be a maximum of 100")
+	// Check that the sort parameter matches a value in the safelist.
+	v.Check(validator.PermittedValue(f.Sort, f.SortSafelist...), "sort", "invalid sort value")
+}
+
+// sortColumn checks that the client-provided Sort field matches one of the entries in
+// the safelist and if it does, extracts the column name from the Sort field by
+// stripping the leading hyphen character (if one exists). The columns map translates
+// the public sort key into the real column name where the two differ.
+func (f Filters) sortColumn(columns map[string]string) string {
+	for _, safeValue := range f.SortSafelist {
+		if f.Sort == safeValue {
+			key := strings.TrimPrefix(f.Sort, "-")
+			if column, ok := columns[key]; ok {
+				return column
+			}
+			return key
+		}
+	}
+	// The safelist is checked by ValidateFilters(), so reaching this point means
+	// a handler forgot to validate its input. That's a bug, not a client error.
+	panic("unsafe sort parameter: " + f.Sort)
+}
+
+// sortDirection returns the sort direction ("ASC" or "DESC") depending on the prefix
+// character of the Sort field.
+func (f Filters) sortDirection() string {
+	if strings.HasPrefix(f.Sort, "-") {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// SortKey returns the sort key without its "-" prefix and whether the order is
+// descending, for implementations which sort in Go rather than in SQL. Like
+// sortColumn() it panics on a value missing from the safelist.
+func (f Filters) SortKey() (string, bool) {
+	return f.sortColumn(nil), f.sortDirection() == "DESC"
+}
+
+func (f Filters) limit() int {
+	return f.PageSize
+}
+
+func (f Filters) offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+// Metadata holds the pagination metadata returned alongside a page of records.
+type Metadata struct {
+	CurrentPage  int `json:"current_page,omitempty"`
+	PageSize     int `json:"page_size,omitempty"`
+	FirstPage    int `json:"first_page,omitempty"`
+	LastPage     int `json:"last_page,omitempty"`
+	TotalRecords int `json:"total_records,omitempty"`
+}
+
+// Paginate returns the requested page of records which are already filtered and
+// sorted, along with the pagination metadata. It matches what LIMIT, OFFSET and
+// count(*) OVER() give in the SQL queries, including the empty metadata of a page past
+// the last one.
+func Paginate[T any](records []T, f Filters) ([]T, Metadata) {
+	start := f.offset()
+	if start >= len(records) {
+		return []T{}, Metadata{}
+	}
+	end := start + f.limit()
+	if end > len(records) {
+		end = len(records)
+	}
+
+	page := make([]T, end-start)
+	copy(page, records[start:end])
+	return page, calculateMetadata(len(records), f.Page, f.PageSize)
+}
+
+// calculateMetadata calculates the pagination metadata values given the total number
+// of records, current page, and page size values. Note that when there are no
+// records an empty Metadata struct is returned.
+func calculateMetadata(totalRecords, page, pageSize int) Metadata {
+	if totalRecords == 0 {
+		return Metadata{}
+	}
+	return Metadata{
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		FirstPage:    1,
+		LastPage:     int(math.Ceil(float64(totalRecords) / float64(pageSize))),
+		TotalRecords: totalRecords,
+	}
+}
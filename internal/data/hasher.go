@@ -0,0 +1,161 @@
+package data
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+// ErrUnknownHash is returned when a stored password hash wasn't produced by any of the
+// known hashers.
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// PasswordHasher produces and checks one kind of password hash. The parameters are
+// encoded in the hash itself, so hashes made with different hashers or parameters can
+// live side by side in the password_hash column.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the plaintext password.
+	Hash(plaintext string) ([]byte, error)
+	// Compare reports whether the plaintext matches a hash this hasher recognises.
+	Compare(hash []byte, plaintext string) (bool, error)
+	// Recognises reports whether the hash was produced by this kind of hasher.
+	Recognises(hash []byte) bool
+	// Current reports whether the hash was produced with this hasher's parameters.
+	Current(hash []byte) bool
+}
+
+// Argon2idHasher encodes hashes in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+type Argon2idHasher struct {
+	Memory      uint32 // in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idHasher follows the second recommended option of RFC 9106 scaled down
+// to 64 MiB of memory.
+var DefaultArgon2idHasher = Argon2idHasher{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const argon2idPrefix = "$argon2id$"
+
+func (h Argon2idHasher) Hash(plaintext string) ([]byte, error) {
+	salt := make([]byte, h.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(plaintext), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	encoded := fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+	return []byte(encoded), nil
+}
+
+func (h Argon2idHasher) Compare(hash []byte, plaintext string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(plaintext), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (h Argon2idHasher) Recognises(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte(argon2idPrefix))
+}
+
+func (h Argon2idHasher) Current(hash []byte) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false
+	}
+	return params.Memory == h.Memory && params.Iterations == h.Iterations && params.Parallelism == h.Parallelism &&
+		uint32(len(salt)) == h.SaltLength && uint32(len(key)) == h.KeyLength
+}
+
+func decodeArgon2id(hash []byte) (params Argon2idHasher, salt, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %q", parts[2])
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return params, nil, nil, err
+	}
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	return params, salt, key, nil
+}
+
+// BcryptHasher is what every password was hashed with before Argon2id; it is kept so
+// that existing hashes still verify.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(plaintext string) ([]byte, error) {
+	// $2b$[cost]$[22-character salt][31-character hash]
+	return bcrypt.GenerateFromPassword([]byte(plaintext), h.Cost)
+}
+
+func (h BcryptHasher) Compare(hash []byte, plaintext string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword(hash, []byte(plaintext))
+	if err != nil {
+		switch {
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (h BcryptHasher) Recognises(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte("$2a$")) || bytes.HasPrefix(hash, []byte("$2b$")) || bytes.HasPrefix(hash, []byte("$2y$"))
+}
+
+func (h BcryptHasher) Current(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err == nil && cost == h.Cost
+}
+
+var (
+	// passwordHasher hashes every new password.
+	passwordHasher PasswordHasher = DefaultArgon2idHasher
+	// knownHashers are tried in order to find the one that produced a stored hash.
+	knownHashers = []PasswordHasher{DefaultArgon2idHasher, BcryptHasher{Cost: 12}}
+)
+
+// SetPasswordHasher changes the hasher used for new passwords. Hashes made by any
+// other known hasher still verify, but Matches() reports them as outdated. It should be
+// called once at start up.
+func SetPasswordHasher(h PasswordHasher) {
+	passwordHasher = h
+}
@@ -0,0 +1,43 @@
+package data
+
+import "testing"
+
+// Cheap parameters keep the test fast; they are only compared, never relied upon.
+var testArgon2idHasher = Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestPasswordMatches(t *testing.T) {
+	defer SetPasswordHasher(passwordHasher)
+
+	tests := []struct {
+		name         string
+		hashWith     PasswordHasher
+		configured   PasswordHasher
+		wantOutdated bool
+	}{
+		{"same hasher", testArgon2idHasher, testArgon2idHasher, false},
+		{"bcrypt to argon2id", BcryptHasher{Cost: 4}, testArgon2idHasher, true},
+		{"argon2id parameters raised", testArgon2idHasher, Argon2idHasher{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}, true},
+		{"bcrypt cost raised", BcryptHasher{Cost: 4}, BcryptHasher{Cost: 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPasswordHasher(tt.hashWith)
+			var p password
+			if err := p.Set("pa55word1234"); err != nil {
+				t.Fatal(err)
+			}
+
+			SetPasswordHasher(tt.configured)
+			match, outdated, err := p.Matches("pa55word1234")
+			if err != nil || !match || outdated != tt.wantOutdated {
+				t.Fatalf("Matches() = %v, %v, %v; want true, %v, nil", match, outdated, err, tt.wantOutdated)
+			}
+
+			match, _, err = p.Matches("wrong password")
+			if err != nil || match {
+				t.Fatalf("Matches() with the wrong password = %v, %v", match, err)
+			}
+		})
+	}
+}
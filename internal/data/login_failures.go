@@ -0,0 +1,101 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// LoginFailure counts the failed logins for one key, such as "email:alice@example.com"
+// or "ip:203.0.113.7".
+type LoginFailure struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time // zero unless the key is locked
+}
+
+type LoginFailureRepository interface {
+	Get(key string) (*LoginFailure, error)
+	Record(key string, window time.Duration) (*LoginFailure, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+type LoginFailureRepo struct {
+	DB *sql.DB
+}
+
+// Get() returns the failures recorded for the key, or ErrRecordNotFound if there are
+// none.
+func (m LoginFailureRepo) Get(key string) (*LoginFailure, error) {
+	query := `
+			SELECT key, failures, last_failure, locked_until
+			FROM login_failures
+			WHERE key = $1`
+	var (
+		failure     LoginFailure
+		lockedUntil sql.NullTime
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, key).Scan(&failure.Key, &failure.Failures, &failure.LastFailure, &lockedUntil)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	failure.LockedUntil = lockedUntil.Time
+	return &failure, nil
+}
+
+// Record() counts one more failed login for the key. Failures older than window are
+// forgotten, so the count restarts at 1 after a quiet period.
+func (m LoginFailureRepo) Record(key string, window time.Duration) (*LoginFailure, error) {
+	query := `
+			INSERT INTO login_failures (key, failures, last_failure)
+			VALUES ($1, 1, NOW())
+			ON CONFLICT (key) DO UPDATE
+			SET failures = CASE WHEN login_failures.last_failure < $2 THEN 1 ELSE login_failures.failures + 1 END,
+			    last_failure = NOW()
+			RETURNING key, failures, last_failure, locked_until`
+	var (
+		failure     LoginFailure
+		lockedUntil sql.NullTime
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, key, time.Now().Add(-window)).Scan(&failure.Key, &failure.Failures, &failure.LastFailure, &lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	failure.LockedUntil = lockedUntil.Time
+	return &failure, nil
+}
+
+// Lock() stops the key from logging in until the given time.
+func (m LoginFailureRepo) Lock(key string, until time.Time) error {
+	query := `
+			UPDATE login_failures
+			SET locked_until = $2
+			WHERE key = $1`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, query, key, until)
+	return err
+}
+
+// Reset() forgets the failures and any lock of the key.
+func (m LoginFailureRepo) Reset(key string) error {
+	query := `
+			DELETE FROM login_failures
+			WHERE key = $1`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, query, key)
+	return err
+}
@@ -0,0 +1,155 @@
+// Package memory implements the repositories of the data package in memory, for tests
+// and local demos which shouldn't need Postgres. The repositories follow the Postgres
+// ones closely: they return the same errors, and the records they hand out are copies,
+// so that changing one doesn't change what is stored.
+package memory
+
+import (
+	"fmt"
+	"github.com/bxiit/greenlight/internal/data"
+	"sync"
+	"time"
+)
+
+// DB plays the part of the database: it holds the records of every repository built
+// on it behind one mutex, so that e.g. UserInfoRepo.GetForToken() sees the tokens
+// stored by TokenRepo, and deleting a user info removes its tokens like ON DELETE
+// CASCADE does.
+type DB struct {
+	mu sync.Mutex
+
+	userInfos      map[int64]*data.UserInfo
+	lastUserInfoID int64
+
+	moduleInfos      map[int64]*data.ModuleInfo
+	lastModuleInfoID int64
+
+	departmentInfos      map[int64]*data.DepartmentInfo
+	lastDepartmentInfoID int64
+
+	// tokens holds the user_info_tokens rows by hash, userTokens the rows of the
+	// legacy tokens table.
+	tokens     map[string]*storedToken
+	userTokens map[string]*data.Token
+
+	// emailChanges holds the new email address by the hash of its token.
+	emailChanges map[string]string
+
+	// permissions holds the codes in the order they were inserted, which is the
+	// order of their ids.
+	permissions         []string
+	userInfoPermissions map[int64]map[string]bool
+
+	roles         []*storedRole
+	userInfoRoles map[int64]map[int64]bool
+
+	loginFailures map[string]*data.LoginFailure
+
+	mfa           map[int64]*data.MFA
+	recoveryCodes map[int64]map[string]bool
+
+	permissionAudit []*data.PermissionAuditEntry
+}
+
+type storedToken struct {
+	data.Token
+	used bool
+}
+
+type storedRole struct {
+	id          int64
+	name        string
+	permissions map[string]bool
+}
+
+// NewDB returns an empty DB holding the permissions and roles which the migrations
+// insert, so that it starts out like a freshly migrated database.
+func NewDB() *DB {
+	db := &DB{
+		userInfos:           make(map[int64]*data.UserInfo),
+		moduleInfos:         make(map[int64]*data.ModuleInfo),
+		departmentInfos:     make(map[int64]*data.DepartmentInfo),
+		tokens:              make(map[string]*storedToken),
+		userTokens:          make(map[string]*data.Token),
+		emailChanges:        make(map[string]string),
+		userInfoPermissions: make(map[int64]map[string]bool),
+		userInfoRoles:       make(map[int64]map[int64]bool),
+		loginFailures:       make(map[string]*data.LoginFailure),
+		mfa:                 make(map[int64]*data.MFA),
+		recoveryCodes:       make(map[int64]map[string]bool),
+	}
+
+	db.permissions = []string{
+		"movies:read", "movies:write",
+		"user_info:read", "user_info:write",
+		"module_info:read", "module_info:write",
+		"department_info:read", "department_info:write",
+		"roles:read", "roles:write",
+		"permissions:read", "permissions:write",
+	}
+
+	// Regular users can browse modules and departments, admins can do everything.
+	user := &storedRole{id: 1, name: "user", permissions: map[string]bool{
+		"module_info:read":     true,
+		"department_info:read": true,
+	}}
+	admin := &storedRole{id: 2, name: "admin", permissions: make(map[string]bool)}
+	for _, code := range db.permissions {
+		if code != "movies:read" && code != "movies:write" {
+			admin.permissions[code] = true
+		}
+	}
+	db.roles = []*storedRole{user, admin}
+
+	return db
+}
+
+// now returns the current time at the precision of the timestamp(0) columns.
+func now() time.Time {
+	return time.Now().Round(time.Second)
+}
+
+// deleteUserInfo removes the user info and every row referencing it, and clears it
+// from the permission audit like ON DELETE SET NULL. The caller holds the lock.
+func (db *DB) deleteUserInfo(id int64) {
+	delete(db.userInfos, id)
+	for hash, t := range db.tokens {
+		if t.UserID == id {
+			db.deleteToken(hash)
+		}
+	}
+	for _, entry := range db.permissionAudit {
+		if entry.ActorID == id {
+			entry.ActorID = 0
+		}
+		if entry.UserInfoID != nil && *entry.UserInfoID == id {
+			entry.UserInfoID = nil
+		}
+	}
+	delete(db.userInfoPermissions, id)
+	delete(db.userInfoRoles, id)
+	delete(db.mfa, id)
+	delete(db.recoveryCodes, id)
+}
+
+// deleteToken removes the token and the email change made with it. The caller holds
+// the lock.
+func (db *DB) deleteToken(hash string) {
+	delete(db.tokens, hash)
+	delete(db.emailChanges, hash)
+}
+
+// permissionExists reports whether the code was inserted. The caller holds the lock.
+func (db *DB) permissionExists(code string) bool {
+	for _, c := range db.permissions {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyError is returned where Postgres would report a foreign key violation.
+func foreignKeyError(table string, id int64) error {
+	return fmt.Errorf("memory: violates foreign key constraint: no %s row with id %d", table, id)
+}
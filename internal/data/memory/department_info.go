@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"sort"
+	"strings"
+)
+
+type DepartmentInfoRepo struct {
+	DB *DB
+}
+
+// Insert sets the ID and Version of the department info, or returns ErrModuleNotFound
+// if the module it references doesn't exist.
+func (m DepartmentInfoRepo) Insert(departmentInfo *data.DepartmentInfo) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if _, ok := m.DB.moduleInfos[int64(departmentInfo.ModuleId)]; !ok {
+		return data.ErrModuleNotFound
+	}
+
+	m.DB.lastDepartmentInfoID++
+	stored := *departmentInfo
+	stored.ID = int(m.DB.lastDepartmentInfoID)
+	stored.Version = 1
+	m.DB.departmentInfos[m.DB.lastDepartmentInfoID] = &stored
+
+	departmentInfo.ID = stored.ID
+	departmentInfo.Version = stored.Version
+	return nil
+}
+
+func (m DepartmentInfoRepo) Get(id int64) (*data.DepartmentInfo, error) {
+	if id < 1 {
+		return nil, data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.departmentInfos[id]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	departmentInfo := *stored
+	return &departmentInfo, nil
+}
+
+// GetAll filters and sorts like the Postgres query does: the director is matched as a
+// case-insensitive substring, and a zero moduleID or an empty director match anything.
+func (m DepartmentInfoRepo) GetAll(moduleID int, director string, filters data.Filters) ([]*data.DepartmentInfo, data.Metadata, error) {
+	key, desc := filters.SortKey()
+	director = strings.ToLower(director)
+
+	m.DB.mu.Lock()
+	departmentInfos := []*data.DepartmentInfo{}
+	for _, stored := range m.DB.departmentInfos {
+		if moduleID != 0 && stored.ModuleId != moduleID {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(stored.DepartmentDirector), director) {
+			continue
+		}
+		departmentInfo := *stored
+		departmentInfos = append(departmentInfos, &departmentInfo)
+	}
+	m.DB.mu.Unlock()
+
+	sort.Slice(departmentInfos, func(i, j int) bool {
+		a, b := departmentInfos[i], departmentInfos[j]
+		var cmp int
+		switch key {
+		case "id":
+			cmp = compareInt64(int64(a.ID), int64(b.ID))
+		case "department_name":
+			cmp = strings.Compare(a.DepartmentName, b.DepartmentName)
+		case "staff_quantity":
+			cmp = compareInt64(int64(a.StaffQuantity), int64(b.StaffQuantity))
+		case "department_director":
+			cmp = strings.Compare(a.DepartmentDirector, b.DepartmentDirector)
+		}
+		if desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return a.ID < b.ID
+	})
+
+	departmentInfos, metadata := data.Paginate(departmentInfos, filters)
+	return departmentInfos, metadata, nil
+}
+
+// Update only goes through if the version still matches, and sets the new Version on
+// departmentInfo.
+func (m DepartmentInfoRepo) Update(departmentInfo *data.DepartmentInfo) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.departmentInfos[int64(departmentInfo.ID)]
+	if !ok || stored.Version != departmentInfo.Version {
+		return data.ErrEditConflict
+	}
+	if _, ok := m.DB.moduleInfos[int64(departmentInfo.ModuleId)]; !ok {
+		return data.ErrModuleNotFound
+	}
+
+	stored.DepartmentName = departmentInfo.DepartmentName
+	stored.StaffQuantity = departmentInfo.StaffQuantity
+	stored.DepartmentDirector = departmentInfo.DepartmentDirector
+	stored.ModuleId = departmentInfo.ModuleId
+	stored.Version++
+
+	departmentInfo.Version = stored.Version
+	return nil
+}
+
+func (m DepartmentInfoRepo) Delete(id int64) error {
+	if id < 1 {
+		return data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if _, ok := m.DB.departmentInfos[id]; !ok {
+		return data.ErrRecordNotFound
+	}
+	delete(m.DB.departmentInfos, id)
+	return nil
+}
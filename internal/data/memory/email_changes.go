@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"crypto/sha256"
+	"github.com/bxiit/greenlight/internal/data"
+	"time"
+)
+
+type EmailChangeRepo struct {
+	DB *DB
+}
+
+// New creates an email change token for the user info and remembers the new email
+// address it belongs to. The address goes away with the token.
+func (m EmailChangeRepo) New(userID int64, newEmail string, ttl time.Duration) (*data.Token, error) {
+	token, err := data.GenerateToken(userID, ttl, data.ScopeEmailChange)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	err = m.DB.insertToken(token)
+	if err != nil {
+		return nil, err
+	}
+	m.DB.emailChanges[string(token.Hash)] = newEmail
+	return token, nil
+}
+
+func (m EmailChangeRepo) GetNewEmail(tokenPlaintext string) (string, error) {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	t, ok := m.DB.tokens[string(tokenHash[:])]
+	if !ok || t.Scope != data.ScopeEmailChange || !t.Expiry.After(time.Now()) {
+		return "", data.ErrRecordNotFound
+	}
+	newEmail, ok := m.DB.emailChanges[string(tokenHash[:])]
+	if !ok {
+		return "", data.ErrRecordNotFound
+	}
+	return newEmail, nil
+}
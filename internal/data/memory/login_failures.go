@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"time"
+)
+
+type LoginFailureRepo struct {
+	DB *DB
+}
+
+func (m LoginFailureRepo) Get(key string) (*data.LoginFailure, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.loginFailures[key]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	failure := *stored
+	return &failure, nil
+}
+
+// Record counts one more failed login for the key, restarting at 1 if the last one is
+// older than window.
+func (m LoginFailureRepo) Record(key string, window time.Duration) (*data.LoginFailure, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	current := now()
+	stored, ok := m.DB.loginFailures[key]
+	switch {
+	case !ok:
+		stored = &data.LoginFailure{Key: key, Failures: 1}
+		m.DB.loginFailures[key] = stored
+	case stored.LastFailure.Before(current.Add(-window)):
+		stored.Failures = 1
+	default:
+		stored.Failures++
+	}
+	stored.LastFailure = current
+
+	failure := *stored
+	return &failure, nil
+}
+
+func (m LoginFailureRepo) Lock(key string, until time.Time) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if stored, ok := m.DB.loginFailures[key]; ok {
+		stored.LockedUntil = until.Round(time.Second)
+	}
+	return nil
+}
+
+func (m LoginFailureRepo) Reset(key string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	delete(m.DB.loginFailures, key)
+	return nil
+}
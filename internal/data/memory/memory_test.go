@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/data/datatest"
+	"github.com/bxiit/greenlight/internal/data/memory"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	datatest.Run(t, func(t *testing.T) data.Models {
+		return memory.NewModels(memory.NewDB())
+	})
+}
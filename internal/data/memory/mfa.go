@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+)
+
+type MFARepo struct {
+	DB *DB
+}
+
+func (m MFARepo) Get(userID int64) (*data.MFA, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.mfa[userID]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	mfa := *stored
+	return &mfa, nil
+}
+
+// SetSecret starts (or restarts) an enrollment, disabling MFA until Enable() is
+// called.
+func (m MFARepo) SetSecret(userID int64, secret string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if _, ok := m.DB.userInfos[userID]; !ok {
+		return foreignKeyError("user_info", userID)
+	}
+	m.DB.mfa[userID] = &data.MFA{UserID: userID, Secret: secret}
+	return nil
+}
+
+// Enable turns MFA on and replaces the recovery codes, or returns ErrRecordNotFound if
+// no enrollment was started.
+func (m MFARepo) Enable(userID int64, recoveryCodes []string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.mfa[userID]
+	if !ok {
+		return data.ErrRecordNotFound
+	}
+	stored.Enabled = true
+
+	hashes := make(map[string]bool)
+	for _, code := range recoveryCodes {
+		hashes[string(data.RecoveryCodeHash(code))] = true
+	}
+	m.DB.recoveryCodes[userID] = hashes
+	return nil
+}
+
+func (m MFARepo) Disable(userID int64) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	delete(m.DB.mfa, userID)
+	delete(m.DB.recoveryCodes, userID)
+	return nil
+}
+
+// UseStep returns false if a code of the step, or of a later one, was already used.
+func (m MFARepo) UseStep(userID int64, step int64) (bool, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.mfa[userID]
+	if !ok || stored.LastUsedStep >= step {
+		return false, nil
+	}
+	stored.LastUsedStep = step
+	return true, nil
+}
+
+func (m MFARepo) UseRecoveryCode(userID int64, code string) (bool, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	hash := string(data.RecoveryCodeHash(code))
+	if !m.DB.recoveryCodes[userID][hash] {
+		return false, nil
+	}
+	delete(m.DB.recoveryCodes[userID], hash)
+	return true, nil
+}
+
+func (m MFARepo) MissingForRoles(userID int64, roles []string) (bool, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if stored, ok := m.DB.mfa[userID]; ok && stored.Enabled {
+		return false, nil
+	}
+	for _, name := range roles {
+		r := m.DB.roleNamed(name)
+		if r != nil && m.DB.userInfoRoles[userID][r.id] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+)
+
+// NewModels returns the Models backed by db. Movies and Users have no repository
+// interface and are left with a nil *sql.DB, so they mustn't be used.
+func NewModels(db *DB) data.Models {
+	return data.Models{
+		ModuleInfos:     ModuleInfoRepo{DB: db},
+		DepartmentInfos: DepartmentInfoRepo{DB: db},
+		Permissions:     PermissionRepo{DB: db},
+		Tokens:          TokenRepo{DB: db},
+		UserInfos:       UserInfoRepo{DB: db},
+		EmailChanges:    EmailChangeRepo{DB: db},
+		Roles:           RoleRepo{DB: db},
+		PermissionAudit: PermissionAuditRepo{DB: db},
+		LoginFailures:   LoginFailureRepo{DB: db},
+		MFA:             MFARepo{DB: db},
+	}
+}
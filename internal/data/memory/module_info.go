@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bxiit/greenlight/internal/data"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errModuleDurationCheck is returned where Postgres would report a violation of the
+// module_duration_check constraint.
+var errModuleDurationCheck = errors.New(`memory: new row for relation "module_info" violates check constraint "module_duration_check"`)
+
+type ModuleInfoRepo struct {
+	DB *DB
+}
+
+// Create sets the ID, CreatedAt and Version of the module info, like the RETURNING
+// clause of the Postgres query.
+func (m ModuleInfoRepo) Create(moduleInfo *data.ModuleInfo) error {
+	if !moduleDurationValid(moduleInfo) {
+		return errModuleDurationCheck
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	m.DB.lastModuleInfoID++
+	stored := *moduleInfo
+	stored.ID = int(m.DB.lastModuleInfoID)
+	stored.CreatedAt = now()
+	stored.UpdatedAt = stored.CreatedAt
+	stored.Version = "1"
+	m.DB.moduleInfos[m.DB.lastModuleInfoID] = &stored
+
+	moduleInfo.ID = stored.ID
+	moduleInfo.CreatedAt = stored.CreatedAt
+	moduleInfo.Version = stored.Version
+	return nil
+}
+
+func (m ModuleInfoRepo) Get(id int64) (*data.ModuleInfo, error) {
+	if id < 1 {
+		return nil, data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.moduleInfos[id]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	moduleInfo := *stored
+	return &moduleInfo, nil
+}
+
+// GetAll filters and sorts like the Postgres query does. The moduleName matches when
+// every word of it is a word of the module name, which is what the 'simple' text
+// search configuration amounts to.
+func (m ModuleInfoRepo) GetAll(moduleName string, examType string, minDuration int, maxDuration int, filters data.Filters) ([]*data.ModuleInfo, data.Metadata, error) {
+	key, desc := filters.SortKey()
+	queryWords := words(moduleName)
+
+	m.DB.mu.Lock()
+	moduleInfos := []*data.ModuleInfo{}
+	for _, stored := range m.DB.moduleInfos {
+		if !containsWords(words(stored.ModuleName), queryWords) {
+			continue
+		}
+		if examType != "" && stored.ExamType != examType {
+			continue
+		}
+		if minDuration != 0 && int(stored.ModuleDuration) < minDuration {
+			continue
+		}
+		if maxDuration != 0 && int(stored.ModuleDuration) > maxDuration {
+			continue
+		}
+		moduleInfo := *stored
+		moduleInfos = append(moduleInfos, &moduleInfo)
+	}
+	m.DB.mu.Unlock()
+
+	sort.Slice(moduleInfos, func(i, j int) bool {
+		a, b := moduleInfos[i], moduleInfos[j]
+		var cmp int
+		switch key {
+		case "id":
+			cmp = compareInt64(int64(a.ID), int64(b.ID))
+		case "module_name":
+			cmp = strings.Compare(a.ModuleName, b.ModuleName)
+		case "module_duration":
+			cmp = compareInt64(int64(a.ModuleDuration), int64(b.ModuleDuration))
+		case "exam_type":
+			cmp = strings.Compare(a.ExamType, b.ExamType)
+		case "created_at":
+			cmp = compareTime(a.CreatedAt, b.CreatedAt)
+		}
+		if desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return a.ID < b.ID
+	})
+
+	moduleInfos, metadata := data.Paginate(moduleInfos, filters)
+	return moduleInfos, metadata, nil
+}
+
+// Update only goes through if the version still matches. It sets the new UpdatedAt and
+// Version on moduleInfo.
+func (m ModuleInfoRepo) Update(moduleInfo *data.ModuleInfo) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.moduleInfos[int64(moduleInfo.ID)]
+	if !ok || stored.Version != moduleInfo.Version {
+		return data.ErrEditConflict
+	}
+	if !moduleDurationValid(moduleInfo) {
+		return errModuleDurationCheck
+	}
+
+	version, err := strconv.Atoi(stored.Version)
+	if err != nil {
+		return err
+	}
+	stored.ModuleName = moduleInfo.ModuleName
+	stored.ModuleDuration = moduleInfo.ModuleDuration
+	stored.ExamType = moduleInfo.ExamType
+	stored.UpdatedAt = now()
+	stored.Version = strconv.Itoa(version + 1)
+
+	moduleInfo.UpdatedAt = stored.UpdatedAt
+	moduleInfo.Version = stored.Version
+	return nil
+}
+
+func (m ModuleInfoRepo) Delete(id int64) error {
+	if id < 1 {
+		return data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if _, ok := m.DB.moduleInfos[id]; !ok {
+		return data.ErrRecordNotFound
+	}
+	// department_info.module_id has no ON DELETE action, so a referenced module stays.
+	for _, departmentInfo := range m.DB.departmentInfos {
+		if int64(departmentInfo.ModuleId) == id {
+			return fmt.Errorf("memory: module_info row with id %d is still referenced from department_info", id)
+		}
+	}
+	delete(m.DB.moduleInfos, id)
+	return nil
+}
+
+// moduleDurationValid mirrors the module_duration_check constraint.
+func moduleDurationValid(moduleInfo *data.ModuleInfo) bool {
+	return moduleInfo.ModuleDuration > 5 && moduleInfo.ModuleDuration < 15
+}
+
+// words splits s into lower case words the way to_tsvector('simple', s) does.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func containsWords(haystack, needles []string) bool {
+	for _, needle := range needles {
+		found := false
+		for _, word := range haystack {
+			if word == needle {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+)
+
+type PermissionAuditRepo struct {
+	DB *DB
+}
+
+// Insert sets the ID and CreatedAt of the entry.
+func (m PermissionAuditRepo) Insert(entry *data.PermissionAuditEntry) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	err := m.DB.checkPermissionAudit(entry)
+	if err != nil {
+		return err
+	}
+	m.DB.insertPermissionAudit(entry)
+	return nil
+}
+
+// checkPermissionAudit returns the error the foreign keys of the permission_audit
+// table would give for the entry. The caller holds the lock.
+func (db *DB) checkPermissionAudit(entry *data.PermissionAuditEntry) error {
+	if _, ok := db.userInfos[entry.ActorID]; !ok {
+		return foreignKeyError("user_info", entry.ActorID)
+	}
+	if entry.UserInfoID != nil {
+		if _, ok := db.userInfos[*entry.UserInfoID]; !ok {
+			return foreignKeyError("user_info", *entry.UserInfoID)
+		}
+	}
+	return nil
+}
+
+// insertPermissionAudit stores an entry which passed checkPermissionAudit. The caller
+// holds the lock.
+func (db *DB) insertPermissionAudit(entry *data.PermissionAuditEntry) {
+	stored := *entry
+	stored.ID = int64(len(db.permissionAudit) + 1)
+	stored.CreatedAt = now()
+	if entry.UserInfoID != nil {
+		userInfoID := *entry.UserInfoID
+		stored.UserInfoID = &userInfoID
+	}
+	db.permissionAudit = append(db.permissionAudit, &stored)
+
+	entry.ID = stored.ID
+	entry.CreatedAt = stored.CreatedAt
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"sort"
+)
+
+type PermissionRepo struct {
+	DB *DB
+}
+
+func (m PermissionRepo) Insert(code string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	return m.DB.insertPermission(code)
+}
+
+// insertPermission adds the code. The caller holds the lock.
+func (db *DB) insertPermission(code string) error {
+	if db.permissionExists(code) {
+		return data.ErrDuplicatePermission
+	}
+	db.permissions = append(db.permissions, code)
+	return nil
+}
+
+func (m PermissionRepo) ListAll() (data.Permissions, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	permissions := append(data.Permissions{}, m.DB.permissions...)
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+// GetAllForUser serves the legacy users table, which has no in-memory counterpart, so
+// no user has any permission.
+func (m PermissionRepo) GetAllForUser(userID int64) (data.Permissions, error) {
+	return nil, nil
+}
+
+// GetAllForUserInfo returns the codes granted directly together with the codes of the
+// user info's roles. Like the Postgres query it returns nil when there are none.
+func (m PermissionRepo) GetAllForUserInfo(userID int64) (data.Permissions, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	codes := make(map[string]bool)
+	for code := range m.DB.userInfoPermissions[userID] {
+		codes[code] = true
+	}
+	for _, r := range m.DB.roles {
+		if !m.DB.userInfoRoles[userID][r.id] {
+			continue
+		}
+		for code := range r.permissions {
+			codes[code] = true
+		}
+	}
+
+	var permissions data.Permissions
+	for code := range codes {
+		permissions = append(permissions, code)
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+func (m PermissionRepo) GetGrantedForUserInfo(userID int64) (data.Permissions, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	permissions := data.Permissions{}
+	for code := range m.DB.userInfoPermissions[userID] {
+		permissions = append(permissions, code)
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+// AddForUser grants the codes which exist and skips the others, like the INSERT ...
+// SELECT of the Postgres implementation.
+func (m PermissionRepo) AddForUser(userID int64, codes ...string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	return m.DB.addForUser(userID, codes...)
+}
+
+// addForUser grants the codes. The caller holds the lock.
+func (db *DB) addForUser(userID int64, codes ...string) error {
+	for _, code := range codes {
+		if !db.permissionExists(code) {
+			continue
+		}
+		if _, ok := db.userInfos[userID]; !ok {
+			return foreignKeyError("user_info", userID)
+		}
+		if db.userInfoPermissions[userID] == nil {
+			db.userInfoPermissions[userID] = make(map[string]bool)
+		}
+		db.userInfoPermissions[userID][code] = true
+	}
+	return nil
+}
+
+func (m PermissionRepo) RemoveForUser(userID int64, codes ...string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	for _, code := range codes {
+		delete(m.DB.userInfoPermissions[userID], code)
+	}
+	return nil
+}
+
+// The audited methods check the audit entry before making the change, so that like
+// the Postgres transaction they keep either both or neither.
+
+func (m PermissionRepo) InsertAudited(actorID int64, code string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	entry := &data.PermissionAuditEntry{ActorID: actorID, Action: data.AuditActionCreate, Code: code}
+	err := m.DB.checkPermissionAudit(entry)
+	if err != nil {
+		return err
+	}
+	err = m.DB.insertPermission(code)
+	if err != nil {
+		return err
+	}
+	m.DB.insertPermissionAudit(entry)
+	return nil
+}
+
+func (m PermissionRepo) AddForUserAudited(actorID, userID int64, code string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	entry := &data.PermissionAuditEntry{ActorID: actorID, Action: data.AuditActionGrant, Code: code, UserInfoID: &userID}
+	err := m.DB.checkPermissionAudit(entry)
+	if err != nil {
+		return err
+	}
+	err = m.DB.addForUser(userID, code)
+	if err != nil {
+		return err
+	}
+	m.DB.insertPermissionAudit(entry)
+	return nil
+}
+
+func (m PermissionRepo) RemoveForUserAudited(actorID, userID int64, code string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	entry := &data.PermissionAuditEntry{ActorID: actorID, Action: data.AuditActionRevoke, Code: code, UserInfoID: &userID}
+	err := m.DB.checkPermissionAudit(entry)
+	if err != nil {
+		return err
+	}
+	delete(m.DB.userInfoPermissions[userID], code)
+	m.DB.insertPermissionAudit(entry)
+	return nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"github.com/bxiit/greenlight/internal/data"
+	"sort"
+)
+
+type RoleRepo struct {
+	DB *DB
+}
+
+// Insert sets the ID of the role. Nothing is stored if the name is taken or one of
+// the codes doesn't exist.
+func (m RoleRepo) Insert(role *data.Role) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if m.DB.roleNamed(role.Name) != nil {
+		return data.ErrDuplicateRole
+	}
+	permissions := make(map[string]bool)
+	for _, code := range role.Permissions {
+		if !m.DB.permissionExists(code) {
+			return data.ErrUnknownPermission
+		}
+		permissions[code] = true
+	}
+
+	id := int64(1)
+	if len(m.DB.roles) > 0 {
+		id = m.DB.roles[len(m.DB.roles)-1].id + 1
+	}
+	m.DB.roles = append(m.DB.roles, &storedRole{id: id, name: role.Name, permissions: permissions})
+	role.ID = id
+	return nil
+}
+
+func (m RoleRepo) GetAll() ([]*data.Role, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	roles := []*data.Role{}
+	for _, r := range m.DB.roles {
+		permissions := data.Permissions{}
+		for code := range r.permissions {
+			permissions = append(permissions, code)
+		}
+		sort.Strings(permissions)
+		roles = append(roles, &data.Role{ID: r.id, Name: r.name, Permissions: permissions})
+	}
+	return roles, nil
+}
+
+func (m RoleRepo) GetAllForUser(userID int64) ([]string, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	names := []string{}
+	for _, r := range m.DB.roles {
+		if m.DB.userInfoRoles[userID][r.id] {
+			names = append(names, r.name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetForUser replaces the roles of the user info and makes the first of them its Role.
+// Nothing changes if one of the names isn't an existing role, in which case
+// ErrRecordNotFound is returned.
+func (m RoleRepo) SetForUser(userID int64, names ...string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	roleIDs := make(map[int64]bool)
+	for _, name := range names {
+		r := m.DB.roleNamed(name)
+		if r == nil {
+			return data.ErrRecordNotFound
+		}
+		roleIDs[r.id] = true
+	}
+	if _, ok := m.DB.userInfos[userID]; !ok && len(roleIDs) > 0 {
+		return foreignKeyError("user_info", userID)
+	}
+
+	m.DB.userInfoRoles[userID] = roleIDs
+	if stored, ok := m.DB.userInfos[userID]; ok {
+		stored.Role = ""
+		if len(names) > 0 {
+			stored.Role = names[0]
+		}
+	}
+	return nil
+}
+
+// roleNamed returns the role with the name, or nil. The caller holds the lock.
+func (db *DB) roleNamed(name string) *storedRole {
+	for _, r := range db.roles {
+		if r.name == name {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/bxiit/greenlight/internal/data"
+	"time"
+)
+
+type TokenRepo struct {
+	DB *DB
+}
+
+func (m TokenRepo) New(userID int64, ttl time.Duration, scope string) (*data.Token, error) {
+	token, err := data.GenerateToken(userID, ttl, scope)
+	if err != nil {
+		return nil, err
+	}
+	err = m.InsertUserInfoToken(token)
+	return token, err
+}
+
+// Insert stores a token of the legacy users table.
+func (m TokenRepo) Insert(token *data.Token) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored := *token
+	m.DB.userTokens[string(token.Hash)] = &stored
+	return nil
+}
+
+func (m TokenRepo) InsertUserInfoToken(token *data.Token) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	return m.DB.insertToken(token)
+}
+
+func (m TokenRepo) DeleteAllForUser(scope string, userID int64) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	for hash, t := range m.DB.tokens {
+		if t.Scope == scope && t.UserID == userID {
+			m.DB.deleteToken(hash)
+		}
+	}
+	return nil
+}
+
+func (m TokenRepo) DeleteForToken(scope string, tokenPlaintext string) error {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	t, ok := m.DB.tokens[string(tokenHash[:])]
+	if !ok || t.Scope != scope {
+		return data.ErrRecordNotFound
+	}
+	m.DB.deleteToken(string(tokenHash[:]))
+	return nil
+}
+
+func (m TokenRepo) DeleteFamilyForToken(scope string, tokenPlaintext string) error {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	t, ok := m.DB.tokens[string(tokenHash[:])]
+	if !ok || t.Scope != scope {
+		return data.ErrRecordNotFound
+	}
+	m.DB.deleteToken(string(tokenHash[:]))
+	m.DB.deleteFamily(t.Family)
+	return nil
+}
+
+// NewPair calls issueAccess without holding the lock, since it may use the other
+// repositories, and then stores both tokens at once.
+func (m TokenRepo) NewPair(userID int64, refreshTTL time.Duration, issueAccess data.IssueAccessFunc) (*data.Token, *data.Token, error) {
+	refresh, err := data.GenerateToken(userID, refreshTTL, data.ScopeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh.Family = refresh.Hash
+
+	access, err := issueAccess(refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	err = m.DB.insertPair(access, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	return access, refresh, nil
+}
+
+func (m TokenRepo) DeleteFamily(family []byte) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	m.DB.deleteFamily(family)
+	return nil
+}
+
+// Rotate follows the Postgres implementation: a used refresh token revokes its whole
+// family and gives ErrTokenReused, an unknown or expired one ErrRecordNotFound. Like
+// NewPair it calls issueAccess without holding the lock, and checks the old token
+// again afterwards in case it was exchanged in the meantime.
+func (m TokenRepo) Rotate(refreshPlaintext string, refreshTTL time.Duration, issueAccess data.IssueAccessFunc) (*data.Token, *data.Token, error) {
+	tokenHash := sha256.Sum256([]byte(refreshPlaintext))
+
+	m.DB.mu.Lock()
+	t, err := m.DB.refreshToken(string(tokenHash[:]))
+	if err != nil {
+		m.DB.mu.Unlock()
+		return nil, nil, err
+	}
+	userID, family := t.UserID, t.Family
+	m.DB.mu.Unlock()
+
+	refresh, err := data.GenerateToken(userID, refreshTTL, data.ScopeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh.Family = family
+
+	access, err := issueAccess(refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	t, err = m.DB.refreshToken(string(tokenHash[:]))
+	if err != nil {
+		return nil, nil, err
+	}
+	err = m.DB.insertPair(access, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.used = true
+	return access, refresh, nil
+}
+
+// refreshToken returns the unused and unexpired refresh token with the hash. A used
+// one revokes its family. The caller holds the lock.
+func (db *DB) refreshToken(hash string) (*storedToken, error) {
+	t, ok := db.tokens[hash]
+	if !ok || t.Scope != data.ScopeRefresh {
+		return nil, data.ErrRecordNotFound
+	}
+	if t.used {
+		db.deleteFamily(t.Family)
+		return nil, data.ErrTokenReused
+	}
+	if !t.Expiry.After(time.Now()) {
+		return nil, data.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+// insertPair stores the refresh token and, unless it has no hash, the access token.
+// Like the transaction in Postgres it stores either both or neither. The caller holds
+// the lock.
+func (db *DB) insertPair(access, refresh *data.Token) error {
+	tokens := []*data.Token{refresh}
+	if access.Hash != nil {
+		tokens = append(tokens, access)
+	}
+	for _, t := range tokens {
+		if _, ok := db.userInfos[t.UserID]; !ok {
+			return foreignKeyError("user_info", t.UserID)
+		}
+	}
+	for _, t := range tokens {
+		err := db.insertToken(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertToken stores a user_info_tokens row. The caller holds the lock.
+func (db *DB) insertToken(t *data.Token) error {
+	if _, ok := db.userInfos[t.UserID]; !ok {
+		return foreignKeyError("user_info", t.UserID)
+	}
+	// The expiry column only keeps whole seconds.
+	stored := &storedToken{Token: *t}
+	stored.Expiry = stored.Expiry.Round(time.Second)
+	db.tokens[string(t.Hash)] = stored
+	return nil
+}
+
+// deleteFamily deletes every token of the family. A nil family, like NULL in SQL,
+// matches nothing. The caller holds the lock.
+func (db *DB) deleteFamily(family []byte) {
+	if family == nil {
+		return
+	}
+	for hash, t := range db.tokens {
+		if t.Family != nil && bytes.Equal(t.Family, family) {
+			db.deleteToken(hash)
+		}
+	}
+}
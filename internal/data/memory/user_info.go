@@ -0,0 +1,265 @@
+package memory
+
+import (
+	"crypto/sha256"
+	"github.com/bxiit/greenlight/internal/data"
+	"sort"
+	"strings"
+	"time"
+)
+
+type UserInfoRepo struct {
+	DB *DB
+}
+
+// Insert sets the ID, CreatedAt and Version of the user info, like the RETURNING
+// clause of the Postgres query. Emails are compared ignoring case, as the citext
+// column does. The user info is given the roles, the first of which becomes its Role;
+// nothing is stored if one of them doesn't exist.
+func (m UserInfoRepo) Insert(userInfo *data.UserInfo, roles ...string) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if m.DB.emailTaken(userInfo.Email, 0) {
+		return data.ErrDuplicateEmail
+	}
+	roleIDs := make(map[int64]bool)
+	for _, name := range roles {
+		r := m.DB.roleNamed(name)
+		if r == nil {
+			return data.ErrRecordNotFound
+		}
+		roleIDs[r.id] = true
+	}
+	if len(roles) > 0 {
+		userInfo.Role = roles[0]
+	}
+
+	m.DB.lastUserInfoID++
+	stored := *userInfo
+	stored.ID = m.DB.lastUserInfoID
+	stored.CreatedAt = now()
+	stored.UpdatedAt = stored.CreatedAt
+	stored.Version = 1
+	m.DB.userInfos[stored.ID] = &stored
+	m.DB.userInfoRoles[stored.ID] = roleIDs
+
+	userInfo.ID = stored.ID
+	userInfo.CreatedAt = stored.CreatedAt
+	userInfo.Version = stored.Version
+	return nil
+}
+
+func (m UserInfoRepo) Get(id int64) (*data.UserInfo, error) {
+	if id < 1 {
+		return nil, data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.userInfos[id]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	userInfo := *stored
+	return &userInfo, nil
+}
+
+func (m UserInfoRepo) GetByEmail(email string) (*data.UserInfo, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	for _, stored := range m.DB.userInfos {
+		if strings.EqualFold(stored.Email, email) {
+			userInfo := *stored
+			return &userInfo, nil
+		}
+	}
+	return nil, data.ErrRecordNotFound
+}
+
+// GetAll filters and sorts like the Postgres query does. As there, the password hash
+// of the returned user infos is left empty.
+func (m UserInfoRepo) GetAll(search string, role string, activated *bool, filters data.Filters) ([]*data.UserInfo, data.Metadata, error) {
+	key, desc := filters.SortKey()
+	search = strings.ToLower(search)
+
+	m.DB.mu.Lock()
+	userInfos := []*data.UserInfo{}
+	for _, stored := range m.DB.userInfos {
+		if search != "" &&
+			!strings.Contains(strings.ToLower(stored.Name), search) &&
+			!strings.Contains(strings.ToLower(stored.Surname), search) &&
+			!strings.Contains(strings.ToLower(stored.Email), search) {
+			continue
+		}
+		if role != "" && stored.Role != role {
+			continue
+		}
+		if activated != nil && stored.Activated != *activated {
+			continue
+		}
+		userInfos = append(userInfos, &data.UserInfo{
+			ID:        stored.ID,
+			CreatedAt: stored.CreatedAt,
+			UpdatedAt: stored.UpdatedAt,
+			Name:      stored.Name,
+			Surname:   stored.Surname,
+			Email:     stored.Email,
+			Role:      stored.Role,
+			Activated: stored.Activated,
+			Version:   stored.Version,
+		})
+	}
+	m.DB.mu.Unlock()
+
+	sort.Slice(userInfos, func(i, j int) bool {
+		a, b := userInfos[i], userInfos[j]
+		var cmp int
+		switch key {
+		case "id":
+			cmp = compareInt64(a.ID, b.ID)
+		case "created_at":
+			cmp = compareTime(a.CreatedAt, b.CreatedAt)
+		case "email":
+			cmp = strings.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email))
+		case "name":
+			cmp = strings.Compare(a.Name, b.Name)
+		case "surname":
+			cmp = strings.Compare(a.Surname, b.Surname)
+		}
+		if desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return a.ID < b.ID
+	})
+
+	userInfos, metadata := data.Paginate(userInfos, filters)
+	return userInfos, metadata, nil
+}
+
+// Update writes every field except the role, like the Postgres query, and only if the
+// version still matches. It sets the new Version on userInfo.
+func (m UserInfoRepo) Update(userInfo *data.UserInfo) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	stored, ok := m.DB.userInfos[userInfo.ID]
+	if !ok || stored.Version != userInfo.Version {
+		return data.ErrEditConflict
+	}
+	if m.DB.emailTaken(userInfo.Email, userInfo.ID) {
+		return data.ErrDuplicateEmail
+	}
+
+	createdAt, role := stored.CreatedAt, stored.Role
+	*stored = *userInfo
+	stored.CreatedAt, stored.Role = createdAt, role
+	stored.UpdatedAt = now()
+	stored.Version++
+
+	userInfo.Version = stored.Version
+	return nil
+}
+
+func (m UserInfoRepo) Delete(id int64) error {
+	if id < 1 {
+		return data.ErrRecordNotFound
+	}
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	if _, ok := m.DB.userInfos[id]; !ok {
+		return data.ErrRecordNotFound
+	}
+	m.DB.deleteUserInfo(id)
+	return nil
+}
+
+func (m UserInfoRepo) GetForToken(tokenScope, tokenPlaintext string) (*data.UserInfo, error) {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	t, ok := m.DB.tokens[string(tokenHash[:])]
+	if !ok || t.Scope != tokenScope || !t.Expiry.After(time.Now()) {
+		return nil, data.ErrRecordNotFound
+	}
+	stored, ok := m.DB.userInfos[t.UserID]
+	if !ok {
+		return nil, data.ErrRecordNotFound
+	}
+	userInfo := *stored
+	return &userInfo, nil
+}
+
+// FindNotActivatedAndExpired returns the user infos which aren't activated and have
+// an expired token. Like the join in the Postgres query, a user info is returned once
+// for each of its expired tokens.
+func (m UserInfoRepo) FindNotActivatedAndExpired() ([]*data.UserInfo, error) {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	current := time.Now()
+	var userInfos []*data.UserInfo
+	for _, t := range m.DB.tokens {
+		stored, ok := m.DB.userInfos[t.UserID]
+		if !ok || stored.Activated || !t.Expiry.Before(current) {
+			continue
+		}
+		userInfo := *stored
+		userInfos = append(userInfos, &userInfo)
+	}
+	return userInfos, nil
+}
+
+// DeleteExpiredToken deletes every token of the user info, whatever its scope or
+// expiry.
+func (m UserInfoRepo) DeleteExpiredToken(id int64) error {
+	m.DB.mu.Lock()
+	defer m.DB.mu.Unlock()
+
+	for hash, t := range m.DB.tokens {
+		if t.UserID == id {
+			m.DB.deleteToken(hash)
+		}
+	}
+	return nil
+}
+
+// emailTaken reports whether a user info other than the one with exceptID has the
+// email. The caller holds the lock.
+func (db *DB) emailTaken(email string, exceptID int64) bool {
+	for id, stored := range db.userInfos {
+		if id != exceptID && strings.EqualFold(stored.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
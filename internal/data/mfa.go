@@ -0,0 +1,273 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"github.com/bxiit/greenlight/internal/validator"
+	"github.com/lib/pq"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer        = "Greenlight"
+	totpPeriod        = 30
+	recoveryCodeCount = 10
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// MFA holds the TOTP (RFC 6238) secret of a user info. Enabled stays false between
+// starting the enrollment and confirming it with a first code.
+type MFA struct {
+	UserID       int64
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+}
+
+type MFARepository interface {
+	Get(userID int64) (*MFA, error)
+	SetSecret(userID int64, secret string) error
+	Enable(userID int64, recoveryCodes []string) error
+	Disable(userID int64) error
+	UseStep(userID int64, step int64) (bool, error)
+	UseRecoveryCode(userID int64, code string) (bool, error)
+	MissingForRoles(userID int64, roles []string) (bool, error)
+}
+
+type MFARepo struct {
+	DB *sql.DB
+}
+
+// GenerateTOTPKey creates a new random secret for the account and returns it along with
+// its otpauth:// provisioning URI, which authenticator apps read from a QR code.
+func GenerateTOTPKey(accountName string) (secret string, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: accountName,
+		Period:      totpPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// MatchTOTP checks the code against the secret, allowing one period of clock drift
+// either way. It returns the time step the code belongs to so that the caller can
+// refuse to accept the same code twice.
+func MatchTOTP(secret, code string, now time.Time) (int64, bool, error) {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return 0, false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// GenerateRecoveryCodes returns a fresh set of single-use recovery codes, formatted
+// like "ABCD-EFGH".
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		randomBytes := make([]byte, 5)
+		_, err := rand.Read(randomBytes)
+		if err != nil {
+			return nil, err
+		}
+		code := base32.StdEncoding.EncodeToString(randomBytes)
+		codes[i] = code[:4] + "-" + code[4:]
+	}
+	return codes, nil
+}
+
+// RecoveryCodeHash hashes a recovery code, ignoring case, spaces and dashes. Only the
+// hash is ever stored.
+func RecoveryCodeHash(code string) []byte {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(code))
+	return hash[:]
+}
+
+func (m MFARepo) Get(userID int64) (*MFA, error) {
+	query := `
+			SELECT user_info_id, secret, enabled, last_used_step
+			FROM user_info_mfa
+			WHERE user_info_id = $1`
+	var mfa MFA
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, userID).Scan(&mfa.UserID, &mfa.Secret, &mfa.Enabled, &mfa.LastUsedStep)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &mfa, nil
+}
+
+// SetSecret() starts (or restarts) an enrollment. The secret isn't used to log in
+// until Enable() is called.
+func (m MFARepo) SetSecret(userID int64, secret string) error {
+	query := `
+			INSERT INTO user_info_mfa (user_info_id, secret)
+			VALUES ($1, $2)
+			ON CONFLICT (user_info_id) DO UPDATE
+			SET secret = EXCLUDED.secret, enabled = false, last_used_step = 0, created_at = NOW()`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, query, userID, secret)
+	return err
+}
+
+// Enable() turns MFA on and replaces the recovery codes, which are stored hashed.
+func (m MFARepo) Enable(userID int64, recoveryCodes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE user_info_mfa SET enabled = true WHERE user_info_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_info_recovery_codes WHERE user_info_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	hashes := make([][]byte, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = RecoveryCodeHash(code)
+	}
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_info_recovery_codes (user_info_id, hash)
+			SELECT $1, unnest($2::bytea[])`, userID, pq.Array(hashes))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Disable() removes the secret and the recovery codes of the user info.
+func (m MFARepo) Disable(userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_info_recovery_codes WHERE user_info_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_info_mfa WHERE user_info_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep() records that a code of the given time step was accepted. It returns false
+// if a code of that step, or a later one, was already used.
+func (m MFARepo) UseStep(userID int64, step int64) (bool, error) {
+	query := `
+			UPDATE user_info_mfa
+			SET last_used_step = $2
+			WHERE user_info_id = $1 AND last_used_step < $2`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	result, err := m.DB.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// UseRecoveryCode() consumes the recovery code. It returns false if the user info has
+// no such unused code.
+func (m MFARepo) UseRecoveryCode(userID int64, code string) (bool, error) {
+	query := `
+			DELETE FROM user_info_recovery_codes
+			WHERE user_info_id = $1 AND hash = $2`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	result, err := m.DB.ExecContext(ctx, query, userID, RecoveryCodeHash(code))
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// MissingForRoles() reports whether the user info holds one of the roles but hasn't
+// enabled MFA.
+func (m MFARepo) MissingForRoles(userID int64, roles []string) (bool, error) {
+	query := `
+			SELECT EXISTS (
+				SELECT 1
+				FROM user_info_roles
+				INNER JOIN roles ON roles.id = user_info_roles.role_id
+				WHERE user_info_roles.user_info_id = $1 AND roles.name = ANY($2)
+			) AND NOT EXISTS (
+				SELECT 1
+				FROM user_info_mfa
+				WHERE user_info_id = $1 AND enabled
+			)`
+	var missing bool
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, userID, pq.Array(roles)).Scan(&missing)
+	return missing, err
+}
+
+func ValidateTOTPCode(v *validator.Validator, code string) {
+	v.Check(code != "", "code", "must be provided")
+	v.Check(len(code) == 6, "code", "must be 6 digits long")
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"github.com/pquerna/otp/totp"
+	"testing"
+	"time"
+)
+
+func TestMatchTOTP(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1700000000, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name      string
+		drift     int64
+		wantMatch bool
+	}{
+		{"current step", 0, true},
+		{"one step behind", -1, true},
+		{"one step ahead", 1, true},
+		{"two steps behind", -2, false},
+		{"two steps ahead", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.GenerateCodeCustom(secret, time.Unix((current+tt.drift)*totpPeriod, 0), totpOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, match, err := MatchTOTP(secret, code, now)
+			if err != nil || match != tt.wantMatch {
+				t.Fatalf("MatchTOTP() = %d, %v, %v; want a match: %v", step, match, err, tt.wantMatch)
+			}
+			if match && step != current+tt.drift {
+				t.Fatalf("got step %d; want %d", step, current+tt.drift)
+			}
+		})
+	}
+}
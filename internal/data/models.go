@@ -13,15 +13,21 @@ var (
 )
 
 // Create a Models struct which wraps the MovieModel
-// kind of enveloping
+// kind of enveloping. The fields typed as repository interfaces can be backed by
+// another implementation, such as the in-memory one of the memory package.
 type Models struct {
 	Movies          MovieModel
-	ModuleInfos     ModuleInfoRepo
-	DepartmentInfos DepartmentInfoModel
-	Permissions     PermissionRepo // Add a new Permissions field.
+	ModuleInfos     ModuleInfoRepository
+	DepartmentInfos DepartmentInfoRepository
+	Permissions     PermissionRepository // Add a new Permissions field.
 	Users           UserModel
-	Tokens          TokenRepo
-	UserInfos       UserInfoRepo
+	Tokens          TokenRepository
+	UserInfos       UserInfoRepository
+	EmailChanges    EmailChangeRepository
+	Roles           RoleRepository
+	PermissionAudit PermissionAuditRepository
+	LoginFailures   LoginFailureRepository
+	MFA             MFARepository
 }
 
 // method which returns a Models struct containing the initialized MovieModel.
@@ -34,6 +40,11 @@ func NewModels(db *sql.DB) Models {
 		Users:           UserModel{DB: db},
 		Tokens:          TokenRepo{DB: db},
 		UserInfos:       UserInfoRepo{DB: db},
+		EmailChanges:    EmailChangeRepo{DB: db},
+		Roles:           RoleRepo{DB: db},
+		PermissionAudit: PermissionAuditRepo{DB: db},
+		LoginFailures:   LoginFailureRepo{DB: db},
+		MFA:             MFARepo{DB: db},
 	}
 }
 
@@ -53,4 +64,5 @@ type DepartmentInfo struct {
 	StaffQuantity      int    `json:"staffQuantity"`
 	DepartmentDirector string `json:"departmentDirector"`
 	ModuleId           int    `json:"moduleId"`
+	Version            int    `json:"version"`
 }
@@ -1,14 +1,18 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"github.com/bxiit/greenlight/internal/validator"
+	"time"
 )
 
 type ModuleInfoRepository interface {
 	Create(moduleInfo *ModuleInfo) error
 	Get(id int64) (*ModuleInfo, error)
-	GetLatestFifty() ([]*ModuleInfo, error)
+	GetAll(moduleName string, examType string, minDuration int, maxDuration int, filters Filters) ([]*ModuleInfo, Metadata, error)
 	Update(moduleInfo *ModuleInfo) error
 	Delete(id int64) error
 }
@@ -43,7 +47,7 @@ func (m ModuleInfoRepo) Get(id int64) (*ModuleInfo, error) {
 	}
 
 	query := `
-		SELECT *
+		SELECT id, created_at, updated_at, module_name, module_duration, exam_type, version
 		FROM module_info
 		WHERE id = $1`
 
@@ -70,23 +74,43 @@ func (m ModuleInfoRepo) Get(id int64) (*ModuleInfo, error) {
 	return &moduleInfo, nil
 }
 
-func (m ModuleInfoRepo) GetLatestFifty() ([]*ModuleInfo, error) {
-	query := `SELECT * FROM module_info ORDER BY id DESC LIMIT 50`
+// ModuleInfoSortSafelist holds the values accepted by the sort parameter when listing
+// module infos.
+var ModuleInfoSortSafelist = []string{
+	"id", "module_name", "module_duration", "exam_type", "created_at",
+	"-id", "-module_name", "-module_duration", "-exam_type", "-created_at",
+}
 
-	rows, err := m.DB.Query(query)
+// GetAll method for fetching a page of records from the moduleInfos table. The
+// moduleName is matched with full-text search, and an empty examType or a zero
+// duration bound means that filter isn't applied.
+func (m ModuleInfoRepo) GetAll(moduleName string, examType string, minDuration int, maxDuration int, filters Filters) ([]*ModuleInfo, Metadata, error) {
+	query := fmt.Sprintf(`
+		SELECT count(*) OVER(), id, created_at, updated_at, module_name, module_duration, exam_type, version
+		FROM module_info
+		WHERE (to_tsvector('simple', module_name) @@ plainto_tsquery('simple', $1) OR $1 = '')
+		AND (exam_type = $2 OR $2 = '')
+		AND (module_duration >= $3 OR $3 = 0)
+		AND (module_duration <= $4 OR $4 = 0)
+		ORDER BY %s %s, id ASC
+		LIMIT $5 OFFSET $6`, filters.sortColumn(nil), filters.sortDirection())
+
+	args := []interface{}{moduleName, examType, minDuration, maxDuration, filters.limit(), filters.offset()}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
 	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return nil, ErrRecordNotFound
-		default:
-			return nil, err
-		}
+		return nil, Metadata{}, err
 	}
+	defer rows.Close()
 
-	var moduleInfos []*ModuleInfo
+	totalRecords := 0
+	moduleInfos := []*ModuleInfo{}
 	for rows.Next() {
 		moduleInfo := &ModuleInfo{}
 		err = rows.Scan(
+			&totalRecords,
 			&moduleInfo.ID,
 			&moduleInfo.CreatedAt,
 			&moduleInfo.UpdatedAt,
@@ -97,20 +121,55 @@ func (m ModuleInfoRepo) GetLatestFifty() ([]*ModuleInfo, error) {
 		)
 
 		if err != nil {
-			return nil, err
+			return nil, Metadata{}, err
 		}
 
 		moduleInfos = append(moduleInfos, moduleInfo)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
 
-	return moduleInfos, nil
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	return moduleInfos, metadata, nil
+}
+
+func ValidateModuleName(v *validator.Validator, moduleName string) {
+	v.Check(moduleName != "", "moduleName", "must be provided")
+	v.Check(len(moduleName) <= 255, "moduleName", "must not be more than 255 bytes long")
+}
+
+// ValidateModuleDuration mirrors the module_duration_check constraint on the
+// module_info table.
+func ValidateModuleDuration(v *validator.Validator, moduleDuration time.Duration) {
+	v.Check(moduleDuration > 5, "moduleDuration", "must be greater than 5")
+	v.Check(moduleDuration < 15, "moduleDuration", "must be less than 15")
 }
 
-// Update method for updating a specific record in the moduleInfos table.
+func ValidateExamType(v *validator.Validator, examType string) {
+	v.Check(examType != "", "examType", "must be provided")
+	v.Check(len(examType) <= 255, "examType", "must not be more than 255 bytes long")
+}
+
+func ValidateModuleInfo(v *validator.Validator, moduleInfo *ModuleInfo) {
+	ValidateModuleName(v, moduleInfo.ModuleName)
+	ValidateModuleDuration(v, moduleInfo.ModuleDuration)
+	ValidateExamType(v, moduleInfo.ExamType)
+}
+
+// ValidateModuleInfoFilters checks the module-specific listing parameters on top of
+// the shared pagination and sorting ones.
+func ValidateModuleInfoFilters(v *validator.Validator, minDuration int, maxDuration int, filters Filters) {
+	v.Check(minDuration >= 0, "min_duration", "must not be negative")
+	v.Check(maxDuration >= 0, "max_duration", "must not be negative")
+	v.Check(maxDuration == 0 || minDuration <= maxDuration, "max_duration", "must not be less than min_duration")
+	ValidateFilters(v, filters)
+}
+
+// Update method for updating a specific record in the moduleInfos table. The update
+// only goes through if the record still has the version that was read, otherwise
+// ErrEditConflict is returned.
 func (m ModuleInfoRepo) Update(moduleInfo *ModuleInfo) error {
 	query := `UPDATE module_info
 			  SET updated_at = now(),
@@ -118,17 +177,29 @@ func (m ModuleInfoRepo) Update(moduleInfo *ModuleInfo) error {
 			      module_duration = $2,
 			      exam_type = $3,
 			      version = version + 1
-			      WHERE id = $4
-			      RETURNING version`
+			      WHERE id = $4 AND version = $5
+			      RETURNING updated_at, version`
 
 	args := []interface{}{
 		moduleInfo.ModuleName,
 		moduleInfo.ModuleDuration,
 		moduleInfo.ExamType,
 		moduleInfo.ID,
+		moduleInfo.Version,
 	}
 
-	return m.DB.QueryRow(query, args...).Scan(&moduleInfo.Version)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&moduleInfo.UpdatedAt, &moduleInfo.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+	return nil
 }
 
 // Delete method for deleting a specific record from the moduleInfos table.
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"fmt"
+	"github.com/bxiit/greenlight/internal/validator"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is what a new password has to satisfy at registration, password reset
+// and password change. Logging in only applies ValidatePasswordPlaintext(), so
+// tightening the policy never locks anybody out of an existing password.
+type PasswordPolicy struct {
+	MinLength     int // in characters; never less than the 8 bytes ValidatePasswordPlaintext() asks for
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MaxRepeats is the longest allowed run of the same character; 0 means no limit.
+	MaxRepeats int
+	// ForbidPersonalInfo rejects passwords containing the user's name, surname or the
+	// local part of their email address.
+	ForbidPersonalInfo bool
+	// Breached is checked last, if set.
+	Breached *BreachedPasswords
+}
+
+// DefaultPasswordPolicy only asks for what ValidatePasswordPlaintext() does.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8}
+
+var passwordPolicy = DefaultPasswordPolicy
+
+// SetPasswordPolicy changes the policy applied by ValidatePasswordPolicy(). It should
+// be called once at start up.
+func SetPasswordPolicy(p PasswordPolicy) {
+	passwordPolicy = p
+}
+
+// ValidatePasswordPolicy checks a new password against the configured policy. personal
+// holds the user's name, surname and email, whichever are known. The error is only
+// non-nil if the breached passwords file couldn't be read.
+func ValidatePasswordPolicy(v *validator.Validator, plaintext string, personal ...string) error {
+	p := passwordPolicy
+
+	ValidatePasswordPlaintext(v, plaintext)
+	v.Check(utf8.RuneCountInString(plaintext) >= p.MinLength, "password", fmt.Sprintf("must be at least %d characters long", p.MinLength))
+
+	var upper, lower, digit, symbol bool
+	for _, r := range plaintext {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	v.Check(!p.RequireUpper || upper, "password", "must contain an upper case letter")
+	v.Check(!p.RequireLower || lower, "password", "must contain a lower case letter")
+	v.Check(!p.RequireDigit || digit, "password", "must contain a digit")
+	v.Check(!p.RequireSymbol || symbol, "password", "must contain a symbol")
+
+	if p.MaxRepeats > 0 {
+		v.Check(longestRun(plaintext) <= p.MaxRepeats, "password",
+			fmt.Sprintf("must not repeat the same character more than %d times in a row", p.MaxRepeats))
+	}
+
+	if p.ForbidPersonalInfo {
+		v.Check(!containsPersonalInfo(plaintext, personal), "password", "must not contain your name or email address")
+	}
+
+	// Only look a password up once it passes everything else; it saves reading the
+	// file for passwords which are rejected anyway.
+	if _, failed := v.Errors["password"]; p.Breached == nil || failed {
+		return nil
+	}
+	breached, err := p.Breached.Contains(plaintext)
+	if err != nil {
+		return err
+	}
+	v.Check(!breached, "password", "has appeared in a data breach and can't be used")
+	return nil
+}
+
+func longestRun(s string) int {
+	longest, run := 0, 0
+	var prev rune
+	for i, r := range s {
+		if i > 0 && r == prev {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		prev = r
+	}
+	return longest
+}
+
+// containsPersonalInfo reports whether the password contains any of the personal
+// values, ignoring case. Emails are reduced to their local part, and values shorter
+// than three characters are ignored since they would reject too much.
+func containsPersonalInfo(plaintext string, personal []string) bool {
+	plaintext = strings.ToLower(plaintext)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if at := strings.LastIndexByte(value, '@'); at >= 0 {
+			value = value[:at]
+		}
+		if utf8.RuneCountInString(value) >= 3 && strings.Contains(plaintext, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/bxiit/greenlight/internal/validator"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	defer SetPasswordPolicy(passwordPolicy)
+	SetPasswordPolicy(PasswordPolicy{
+		MinLength:          10,
+		RequireUpper:       true,
+		RequireDigit:       true,
+		MaxRepeats:         2,
+		ForbidPersonalInfo: true,
+	})
+
+	tests := []struct {
+		password string
+		wantErr  string
+	}{
+		{"Correct42horse", ""},
+		{"Short4299", "must be at least 10 characters long"},
+		{"correct42horse", "must contain an upper case letter"},
+		{"Correcthorse", "must contain a digit"},
+		{"Correct42hooorse", "must not repeat the same character more than 2 times in a row"},
+		{"Alice42horse", "must not contain your name or email address"},
+		{"Xx-wonderland-1", "must not contain your name or email address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			v := validator.New()
+			err := ValidatePasswordPolicy(v, tt.password, "wonderland@example.com", "Alice", "Li")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.Errors["password"]; got != tt.wantErr {
+				t.Fatalf("password error = %q; want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordsContains(t *testing.T) {
+	// Enough lines for the binary search to run before the final scan.
+	var lines []string
+	for i := 0; i < 2000; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("breached-%d", i)))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	breached, err := OpenBreachedPasswords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer breached.Close()
+
+	for i := range lines {
+		found, err := breached.Contains(fmt.Sprintf("breached-%d", i))
+		if err != nil || !found {
+			t.Fatalf("Contains(breached-%d) = %v, %v; want true", i, found, err)
+		}
+	}
+	for _, password := range []string{"breached-2000", "not breached", ""} {
+		found, err := breached.Contains(password)
+		if err != nil || found {
+			t.Fatalf("Contains(%q) = %v, %v; want false", password, found, err)
+		}
+	}
+}
+
+func TestBreachedPasswordsContainsBuckets(t *testing.T) {
+	dir := t.TempDir()
+	buckets := make(map[string][]string)
+	for i := 0; i < 100; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("breached-%d", i)))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		buckets[hash[:5]] = append(buckets[hash[:5]], fmt.Sprintf("%s:%d", hash[5:], i+1))
+	}
+	for prefix, lines := range buckets {
+		err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Join(lines, "\r\n")), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A bucket which holds other suffixes only.
+	sum := sha1.Sum([]byte("not breached"))
+	prefix := strings.ToUpper(hex.EncodeToString(sum[:]))[:5]
+	if _, ok := buckets[prefix]; !ok {
+		err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Repeat("0", 35)+":1\r\n"), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	breached, err := OpenBreachedPasswords(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer breached.Close()
+
+	for i := 0; i < 100; i++ {
+		found, err := breached.Contains(fmt.Sprintf("breached-%d", i))
+		if err != nil || !found {
+			t.Fatalf("Contains(breached-%d) = %v, %v; want true", i, found, err)
+		}
+	}
+	found, err := breached.Contains("not breached")
+	if err != nil || found {
+		t.Fatalf("Contains(%q) = %v, %v; want false", "not breached", found, err)
+	}
+	// The bucket of this one wasn't written, as in an incomplete download.
+	if _, err := breached.Contains("breached-100"); err == nil {
+		t.Fatal("Contains with a missing bucket succeeded; want an error")
+	}
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// The actions recorded in the permission audit log.
+const (
+	AuditActionCreate = "create"
+	AuditActionGrant  = "grant"
+	AuditActionRevoke = "revoke"
+)
+
+// PermissionAuditEntry records one change made through the permission admin API.
+// UserInfoID is nil for changes which don't target a user info, such as creating a
+// new code.
+type PermissionAuditEntry struct {
+	ID         int64     `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	ActorID    int64     `json:"actor_id"`
+	Action     string    `json:"action"`
+	Code       string    `json:"code"`
+	UserInfoID *int64    `json:"user_info_id,omitempty"`
+}
+
+type PermissionAuditRepository interface {
+	Insert(entry *PermissionAuditEntry) error
+}
+
+type PermissionAuditRepo struct {
+	DB *sql.DB
+}
+
+func (m PermissionAuditRepo) Insert(entry *PermissionAuditEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return insertPermissionAudit(ctx, m.DB, entry)
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx, so that an audit entry can
+// be written on its own or together with the change it records.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertPermissionAudit(ctx context.Context, q queryRower, entry *PermissionAuditEntry) error {
+	query := `
+			INSERT INTO permission_audit (actor_id, action, code, user_info_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at`
+	args := []interface{}{entry.ActorID, entry.Action, entry.Code, entry.UserInfoID}
+	return q.QueryRowContext(ctx, query, args...).Scan(&entry.ID, &entry.CreatedAt)
+}
@@ -3,10 +3,18 @@ package data
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"github.com/bxiit/greenlight/internal/validator"
 	"github.com/lib/pq"
+	"regexp"
 	"time"
 )
 
+var ErrDuplicatePermission = errors.New("duplicate permission code")
+
+// PermissionCodeRX matches permission codes such as "module_info:write".
+var PermissionCodeRX = regexp.MustCompile(`^[a-z][a-z0-9_]*:[a-z][a-z0-9_]*$`)
+
 // Define a Permissions slice, which we will use to hold the permission codes (like
 // "movies:read" and "movies:write") for a single user.
 // "movies:read" and "movies:write") for a single user.
@@ -24,9 +32,16 @@ func (p Permissions) Include(code string) bool {
 }
 
 type PermissionRepository interface {
+	Insert(code string) error
+	ListAll() (Permissions, error)
 	GetAllForUser(userID int64) (Permissions, error)
 	GetAllForUserInfo(userID int64) (Permissions, error)
+	GetGrantedForUserInfo(userID int64) (Permissions, error)
 	AddForUser(userID int64, codes ...string) error
+	RemoveForUser(userID int64, codes ...string) error
+	InsertAudited(actorID int64, code string) error
+	AddForUserAudited(actorID, userID int64, code string) error
+	RemoveForUserAudited(actorID, userID int64, code string) error
 }
 
 // Define the PermissionRepo type.
@@ -34,6 +49,61 @@ type PermissionRepo struct {
 	DB *sql.DB
 }
 
+const insertPermissionQuery = `
+			INSERT INTO permissions (code)
+			VALUES ($1)`
+
+// Insert() adds a new permission code. It returns ErrDuplicatePermission if the code
+// already exists.
+func (m PermissionRepo) Insert(code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, insertPermissionQuery, code)
+	return permissionInsertError(err)
+}
+
+// permissionInsertError turns the unique violation of insertPermissionQuery into
+// ErrDuplicatePermission.
+func permissionInsertError(err error) error {
+	if err != nil {
+		switch {
+		case err.Error() == `pq: duplicate key value violates unique constraint "permissions_code_key"`:
+			return ErrDuplicatePermission
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// ListAll() returns every permission code known to the system.
+func (m PermissionRepo) ListAll() (Permissions, error) {
+	query := `
+			SELECT code
+			FROM permissions
+			ORDER BY code`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	permissions := Permissions{}
+	for rows.Next() {
+		var permission string
+		err := rows.Scan(&permission)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
 // The GetAllForUser() method returns all permission codes for a specific user in a
 // Permissions slice. The code in this method should feel very familiar --- it uses the
 // standard pattern that we've already seen before for retrieving multiple data rows in
@@ -67,13 +137,21 @@ func (m PermissionRepo) GetAllForUser(userID int64) (Permissions, error) {
 	return permissions, nil
 }
 
+// GetAllForUserInfo() returns the effective permission codes of a user info: the
+// codes granted to it directly plus the codes of every role assigned to it.
 func (m PermissionRepo) GetAllForUserInfo(userID int64) (Permissions, error) {
 	query := `
 			SELECT permissions.code
 			FROM permissions
 			INNER JOIN user_info_permissions ON user_info_permissions.permission_id = permissions.id
-			INNER JOIN user_info ON user_info_permissions.user_info_id = user_info.id
-			WHERE user_info.id = $1`
+			WHERE user_info_permissions.user_info_id = $1
+			UNION
+			SELECT permissions.code
+			FROM permissions
+			INNER JOIN role_permissions ON role_permissions.permission_id = permissions.id
+			INNER JOIN user_info_roles ON user_info_roles.role_id = role_permissions.role_id
+			WHERE user_info_roles.user_info_id = $1
+			ORDER BY code`
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	rows, err := m.DB.QueryContext(ctx, query, userID)
@@ -96,12 +174,121 @@ func (m PermissionRepo) GetAllForUserInfo(userID int64) (Permissions, error) {
 	return permissions, nil
 }
 
-func (m PermissionRepo) AddForUser(userID int64, codes ...string) error {
+// GetGrantedForUserInfo() returns only the permission codes granted to a user info
+// directly, leaving out the ones it gets through its roles.
+func (m PermissionRepo) GetGrantedForUserInfo(userID int64) (Permissions, error) {
 	query := `
-			INSERT INTO public.user_info_permissions
-			SELECT $1, permissions.id FROM permissions WHERE permissions.code = ANY($2)`
+			SELECT permissions.code
+			FROM permissions
+			INNER JOIN user_info_permissions ON user_info_permissions.permission_id = permissions.id
+			WHERE user_info_permissions.user_info_id = $1
+			ORDER BY permissions.code`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	permissions := Permissions{}
+	for rows.Next() {
+		var permission string
+		err := rows.Scan(&permission)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+const addForUserQuery = `
+			INSERT INTO user_info_permissions
+			SELECT $1, permissions.id FROM permissions WHERE permissions.code = ANY($2)
+			ON CONFLICT DO NOTHING`
+
+// AddForUser() grants the codes to a user info. Codes it already has are skipped.
+func (m PermissionRepo) AddForUser(userID int64, codes ...string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
 	defer cancel()
-	_, err := m.DB.ExecContext(ctx, query, userID, pq.Array(codes))
+	_, err := m.DB.ExecContext(ctx, addForUserQuery, userID, pq.Array(codes))
+	return err
+}
+
+const removeForUserQuery = `
+			DELETE FROM user_info_permissions
+			USING permissions
+			WHERE user_info_permissions.permission_id = permissions.id
+			AND user_info_permissions.user_info_id = $1
+			AND permissions.code = ANY($2)`
+
+// RemoveForUser() revokes the codes granted directly to a user info. Codes granted
+// through a role are not affected.
+func (m PermissionRepo) RemoveForUser(userID int64, codes ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, removeForUserQuery, userID, pq.Array(codes))
 	return err
 }
+
+// InsertAudited() is Insert() made by the actor, recorded in the permission audit log
+// in the same transaction.
+func (m PermissionRepo) InsertAudited(actorID int64, code string) error {
+	return m.audited(&PermissionAuditEntry{ActorID: actorID, Action: AuditActionCreate, Code: code},
+		func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, insertPermissionQuery, code)
+			return permissionInsertError(err)
+		})
+}
+
+// AddForUserAudited() is AddForUser() of a single code made by the actor, recorded in
+// the permission audit log in the same transaction.
+func (m PermissionRepo) AddForUserAudited(actorID, userID int64, code string) error {
+	return m.audited(&PermissionAuditEntry{ActorID: actorID, Action: AuditActionGrant, Code: code, UserInfoID: &userID},
+		func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, addForUserQuery, userID, pq.Array([]string{code}))
+			return err
+		})
+}
+
+// RemoveForUserAudited() is RemoveForUser() of a single code made by the actor,
+// recorded in the permission audit log in the same transaction.
+func (m PermissionRepo) RemoveForUserAudited(actorID, userID int64, code string) error {
+	return m.audited(&PermissionAuditEntry{ActorID: actorID, Action: AuditActionRevoke, Code: code, UserInfoID: &userID},
+		func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, removeForUserQuery, userID, pq.Array([]string{code}))
+			return err
+		})
+}
+
+// audited runs the change and inserts the audit entry in one transaction, so that
+// neither is kept without the other.
+func (m PermissionRepo) audited(entry *PermissionAuditEntry, change func(ctx context.Context, tx *sql.Tx) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = change(ctx, tx)
+	if err != nil {
+		return err
+	}
+	err = insertPermissionAudit(ctx, tx, entry)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func ValidatePermissionCode(v *validator.Validator, code string) {
+	v.Check(code != "", "code", "must be provided")
+	v.Check(len(code) <= 100, "code", "must not be more than 100 bytes long")
+	v.Check(validator.Matches(code, PermissionCodeRX), "code", "must look like resource:action")
+}
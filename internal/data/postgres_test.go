@@ -0,0 +1,92 @@
+package data_test
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"github.com/bxiit/greenlight/internal/data"
+	"github.com/bxiit/greenlight/internal/data/datatest"
+	"github.com/lib/pq"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDSNEnv names the environment variable holding the DSN of a Postgres database in
+// which the contract tests may create schemas. The tests are skipped if it's empty.
+const testDSNEnv = "GREENLIGHT_TEST_DSN"
+
+func TestPostgresRepositories(t *testing.T) {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	datatest.Run(t, func(t *testing.T) data.Models {
+		return data.NewModels(openTestSchema(t, dsn))
+	})
+}
+
+// openTestSchema creates a schema with a random name, migrates it with the up
+// migrations and returns a pool whose search_path starts with it. The schema is
+// dropped when the test ends.
+func openTestSchema(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := "greenlight_test_" + hex.EncodeToString(suffix)
+
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if err != nil {
+			t.Errorf("drop schema %s: %v", schema, err)
+		}
+	})
+
+	// public stays on the search_path for extensions such as citext which are
+	// already installed there.
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		dsn, err = pq.ParseURL(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	db, err := sql.Open("postgres", dsn+" search_path="+schema+",public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Registered after the DROP SCHEMA cleanup, so it runs first.
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range migrations {
+		query, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(string(query))
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return db
+}
@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/bxiit/greenlight/internal/validator"
+	"github.com/lib/pq"
+	"time"
+)
+
+var (
+	ErrDuplicateRole     = errors.New("duplicate role")
+	ErrUnknownPermission = errors.New("unknown permission code")
+)
+
+// Role is a named set of permission codes which can be assigned to user infos.
+type Role struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Permissions Permissions `json:"permissions"`
+}
+
+type RoleRepository interface {
+	Insert(role *Role) error
+	GetAll() ([]*Role, error)
+	GetAllForUser(userID int64) ([]string, error)
+	SetForUser(userID int64, names ...string) error
+}
+
+type RoleRepo struct {
+	DB *sql.DB
+}
+
+// Insert() creates the role and links it to its permission codes in one transaction.
+// It returns ErrUnknownPermission if any of the codes doesn't exist.
+func (m RoleRepo) Insert(role *Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+			INSERT INTO roles (name)
+			VALUES ($1)
+			RETURNING id`, role.Name).Scan(&role.ID)
+	if err != nil {
+		switch {
+		case err.Error() == `pq: duplicate key value violates unique constraint "roles_name_key"`:
+			return ErrDuplicateRole
+		default:
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+			INSERT INTO role_permissions
+			SELECT $1, permissions.id FROM permissions WHERE permissions.code = ANY($2)`, role.ID, pq.Array(role.Permissions))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(rowsAffected) != len(role.Permissions) {
+		return ErrUnknownPermission
+	}
+
+	return tx.Commit()
+}
+
+// GetAll() returns every role together with its permission codes.
+func (m RoleRepo) GetAll() ([]*Role, error) {
+	query := `
+			SELECT roles.id, roles.name, COALESCE(array_agg(permissions.code ORDER BY permissions.code) FILTER (WHERE permissions.code IS NOT NULL), '{}')
+			FROM roles
+			LEFT JOIN role_permissions ON role_permissions.role_id = roles.id
+			LEFT JOIN permissions ON permissions.id = role_permissions.permission_id
+			GROUP BY roles.id
+			ORDER BY roles.id`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*Role{}
+	for rows.Next() {
+		var role Role
+		err := rows.Scan(&role.ID, &role.Name, pq.Array(&role.Permissions))
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetAllForUser() returns the names of the roles assigned to a user info.
+func (m RoleRepo) GetAllForUser(userID int64) ([]string, error) {
+	query := `
+			SELECT roles.name
+			FROM roles
+			INNER JOIN user_info_roles ON user_info_roles.role_id = roles.id
+			WHERE user_info_roles.user_info_id = $1
+			ORDER BY roles.name`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// SetForUser() replaces the roles of a user info with the named ones, and makes the
+// first of them its user_role, which the role filter of the user info listing and the
+// role claim of access tokens read. It returns ErrRecordNotFound if any of the names
+// isn't an existing role.
+func (m RoleRepo) SetForUser(userID int64, names ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_info_roles WHERE user_info_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+			INSERT INTO user_info_roles
+			SELECT $1, roles.id FROM roles WHERE roles.name = ANY($2)`, userID, pq.Array(names))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(rowsAffected) != len(names) {
+		return ErrRecordNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE user_info SET user_role = $1 WHERE id = $2`, mainRole(names), userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// mainRole returns the role stored as the user_role of a user info with the roles.
+func mainRole(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+func ValidateRole(v *validator.Validator, role *Role) {
+	v.Check(role.Name != "", "name", "must be provided")
+	v.Check(len(role.Name) <= 100, "name", "must not be more than 100 bytes long")
+	v.Check(validator.Unique(role.Permissions), "permissions", "must not contain duplicate values")
+}
+
+func ValidateRoleNames(v *validator.Validator, names []string) {
+	v.Check(len(names) > 0, "roles", "must contain at least 1 role")
+	v.Check(validator.Unique(names), "roles", "must not contain duplicate values")
+}
@@ -6,16 +6,25 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/base32"
+	"errors"
 	"github.com/bxiit/greenlight/internal/validator"
 	"time"
 )
 
+// ErrTokenReused is returned when a refresh token which has already been exchanged is
+// presented again. By then the whole token family has been revoked.
+var ErrTokenReused = errors.New("refresh token reused")
+
 // UserModel.GetForToken(token) → Retrieve the user associated with a token
 // TokenRepo.GetAllForUser(user) → Retrieve all tokens associated with a user
 
 const (
 	ScopeActivation     = "activation"
 	ScopeAuthentication = "authentication" // Include a new authentication scope.
+	ScopePasswordReset  = "password-reset"
+	ScopeEmailChange    = "email-change"
+	ScopeRefresh        = "refresh"
+	ScopeMFAPending     = "mfa-pending"
 )
 
 type TokenRepository interface {
@@ -23,8 +32,19 @@ type TokenRepository interface {
 	Insert(token *Token) error
 	InsertUserInfoToken(token *Token) error
 	DeleteAllForUser(scope string, userID int64) error
+	DeleteForToken(scope string, tokenPlaintext string) error
+	DeleteFamilyForToken(scope string, tokenPlaintext string) error
+	NewPair(userID int64, refreshTTL time.Duration, issueAccess IssueAccessFunc) (*Token, *Token, error)
+	DeleteFamily(family []byte) error
+	Rotate(refreshPlaintext string, refreshTTL time.Duration, issueAccess IssueAccessFunc) (*Token, *Token, error)
 }
 
+// IssueAccessFunc issues the access token which goes with a refresh token. It is
+// called before the transaction storing the refresh token starts, so that it may use
+// the database itself and an error leaves every token as it was. A returned token with a Hash is stored together with the refresh token,
+// while one without, like a signed JWT, isn't stored at all.
+type IssueAccessFunc func(refresh *Token) (*Token, error)
+
 // Define the TokenRepo type.
 type TokenRepo struct {
 	DB *sql.DB
@@ -37,9 +57,12 @@ type Token struct {
 	UserID    int64     `json:"-"`
 	Expiry    time.Time `json:"expiry"`
 	Scope     string    `json:"-"`
+	Family    []byte    `json:"-"`
 }
 
-func generateToken(userID int64, ttl time.Duration, scope string) (*Token, error) {
+// GenerateToken creates a random token for the user without storing it. The
+// repositories' New() methods use it, whichever storage is behind them.
+func GenerateToken(userID int64, ttl time.Duration, scope string) (*Token, error) {
 	// Create a Token instance containing the user ID, expiry, and scope information.
 	// Notice that we add the provided ttl (time-to-live) duration parameter to the
 	// current time to get the expiry time?
@@ -84,7 +107,7 @@ func ValidateTokenPlaintext(v *validator.Validator, tokenPlaintext string) {
 // The New() method is a shortcut which creates a new Token struct and then inserts the
 // data in the tokens table.
 func (m TokenRepo) New(userID int64, ttl time.Duration, scope string) (*Token, error) {
-	token, err := generateToken(userID, ttl, scope)
+	token, err := GenerateToken(userID, ttl, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -117,13 +140,237 @@ func (m TokenRepo) InsertUserInfoToken(token *Token) error {
 	return err
 }
 
-// DeleteAllForUser() deletes all tokens for a specific user and scope.
+// DeleteAllForUser() deletes all tokens for a specific user info and scope.
 func (m TokenRepo) DeleteAllForUser(scope string, userID int64) error {
 	query := `
-			DELETE FROM tokens
-			WHERE scope = $1 AND user_id = $2`
+			DELETE FROM user_info_tokens
+			WHERE scope = $1 AND user_info_id = $2`
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	_, err := m.DB.ExecContext(ctx, query, scope, userID)
 	return err
 }
+
+// DeleteForToken() deletes the single token matching the plaintext and scope. It
+// returns ErrRecordNotFound if no such token exists.
+func (m TokenRepo) DeleteForToken(scope string, tokenPlaintext string) error {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+	query := `
+			DELETE FROM user_info_tokens
+			WHERE hash = $1 AND scope = $2`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	result, err := m.DB.ExecContext(ctx, query, tokenHash[:], scope)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteFamilyForToken() deletes the token matching the plaintext and scope together
+// with every other token of its family, so logging out with an access token also
+// revokes the refresh token issued alongside it. It returns ErrRecordNotFound if no
+// such token exists.
+func (m TokenRepo) DeleteFamilyForToken(scope string, tokenPlaintext string) error {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+	query := `
+			WITH token AS (
+				SELECT hash, family FROM user_info_tokens WHERE hash = $1 AND scope = $2
+			)
+			DELETE FROM user_info_tokens
+			USING token
+			WHERE user_info_tokens.hash = token.hash OR user_info_tokens.family = token.family`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	result, err := m.DB.ExecContext(ctx, query, tokenHash[:], scope)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// NewPair() issues a refresh token (ScopeRefresh) for the user info together with its
+// access token, and returns the access token first. The refresh token starts a new
+// token family named after its hash. Both tokens are stored in one transaction.
+func (m TokenRepo) NewPair(userID int64, refreshTTL time.Duration, issueAccess IssueAccessFunc) (*Token, *Token, error) {
+	refresh, err := GenerateToken(userID, refreshTTL, ScopeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh.Family = refresh.Hash
+
+	access, err := issueAccess(refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	err = insertInFamily(ctx, tx, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	if access.Hash != nil {
+		err = insertInFamily(ctx, tx, access)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return access, refresh, tx.Commit()
+}
+
+// queryExecer is implemented by both *sql.DB and *sql.Tx.
+type queryExecer interface {
+	queryRower
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// usableRefreshToken returns the user info ID and the family of the unused and
+// unexpired refresh token with the hash. A used one has its family deleted and gives
+// ErrTokenReused, an unknown or expired one ErrRecordNotFound. lock is appended to the
+// SELECT.
+func usableRefreshToken(ctx context.Context, q queryExecer, hash []byte, lock string) (int64, []byte, error) {
+	var (
+		userID int64
+		expiry time.Time
+		used   bool
+		family []byte
+	)
+	err := q.QueryRowContext(ctx, `
+			SELECT user_info_id, expiry, used, family
+			FROM user_info_tokens
+			WHERE hash = $1 AND scope = $2 `+lock, hash, ScopeRefresh).Scan(&userID, &expiry, &used, &family)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, nil, ErrRecordNotFound
+		default:
+			return 0, nil, err
+		}
+	}
+
+	if used {
+		_, err = q.ExecContext(ctx, `DELETE FROM user_info_tokens WHERE family = $1`, family)
+		if err != nil {
+			return 0, nil, err
+		}
+		return 0, nil, ErrTokenReused
+	}
+	if !expiry.After(time.Now()) {
+		return 0, nil, ErrRecordNotFound
+	}
+	return userID, family, nil
+}
+
+func insertInFamily(ctx context.Context, tx *sql.Tx, token *Token) error {
+	query := `
+			INSERT INTO user_info_tokens (hash, user_info_id, expiry, scope, family)
+			VALUES ($1, $2, $3, $4, $5)`
+	args := []any{token.Hash, token.UserID, token.Expiry, token.Scope, token.Family}
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteFamily() deletes every token of the family.
+func (m TokenRepo) DeleteFamily(family []byte) error {
+	query := `
+			DELETE FROM user_info_tokens
+			WHERE family = $1`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, query, family)
+	return err
+}
+
+// Rotate() exchanges a refresh token for a new refresh token of the same family and
+// its access token, and returns the access token first. Each refresh token can be
+// exchanged once: presenting it a second time revokes every token in its family and
+// returns ErrTokenReused. An unknown or expired refresh token gives
+// ErrRecordNotFound. The old refresh token is only used up if the new tokens are
+// stored too.
+//
+// issueAccess is called before the transaction starts, as it may read other tables
+// and so need a connection of its own. The old token is checked again under a row
+// lock in case it was exchanged in the meantime.
+func (m TokenRepo) Rotate(refreshPlaintext string, refreshTTL time.Duration, issueAccess IssueAccessFunc) (*Token, *Token, error) {
+	tokenHash := sha256.Sum256([]byte(refreshPlaintext))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	userID, family, err := usableRefreshToken(ctx, m.DB, tokenHash[:], "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refresh, err := GenerateToken(userID, refreshTTL, ScopeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh.Family = family
+
+	access, err := issueAccess(refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the row so that two concurrent exchanges of the same token can't both
+	// see it unused.
+	_, _, err = usableRefreshToken(ctx, tx, tokenHash[:], "FOR UPDATE")
+	if errors.Is(err, ErrTokenReused) {
+		// Keep the revocation of the family.
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			return nil, nil, commitErr
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE user_info_tokens SET used = true WHERE hash = $1`, tokenHash[:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = insertInFamily(ctx, tx, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	if access.Hash != nil {
+		err = insertInFamily(ctx, tx, access)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return access, refresh, tx.Commit()
+}
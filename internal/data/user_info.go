@@ -5,15 +5,17 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/bxiit/greenlight/internal/validator"
+	"github.com/lib/pq"
 	"time"
 )
 
 type UserInfoRepository interface {
-	Insert(userInfo *UserInfo) error
+	Insert(userInfo *UserInfo, roles ...string) error
 	Get(id int64) (*UserInfo, error)
 	GetByEmail(email string) (*UserInfo, error)
-	GetAll() ([]*UserInfo, error)
+	GetAll(search string, role string, activated *bool, filters Filters) ([]*UserInfo, Metadata, error)
 	Update(userInfo *UserInfo) error
 	Delete(id int64) error
 	GetForToken(tokenScope, tokenPlaintext string) (*UserInfo, error)
@@ -48,7 +50,15 @@ func (u *UserInfo) IsAnonymous() bool {
 	return u == AnonymousUserInfo
 }
 
-func (m UserInfoRepo) Insert(userInfo *UserInfo) error {
+// Insert() stores the user info together with the named roles in one transaction, so
+// that an account never exists without them. If roles are given, the first one becomes
+// the Role of the user info, as SetForUser() does. It returns ErrRecordNotFound if any
+// of the names isn't an existing role.
+func (m UserInfoRepo) Insert(userInfo *UserInfo, roles ...string) error {
+	if len(roles) > 0 {
+		userInfo.Role = mainRole(roles)
+	}
+
 	query := `
 			INSERT INTO user_info (fname, sname, email, password_hash, user_role, activated)
 			VALUES ($1, $2, $3, $4, $5, $6)
@@ -56,7 +66,14 @@ func (m UserInfoRepo) Insert(userInfo *UserInfo) error {
 	args := []interface{}{userInfo.Name, userInfo.Surname, userInfo.Email, userInfo.PasswordHashed.hash, userInfo.Role, userInfo.Activated}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&userInfo.ID, &userInfo.CreatedAt, &userInfo.Version)
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&userInfo.ID, &userInfo.CreatedAt, &userInfo.Version)
 	if err != nil {
 		switch {
 		case err.Error() == `pq: duplicate key value violates unique constraint "user_info_email_key"`:
@@ -65,7 +82,24 @@ func (m UserInfoRepo) Insert(userInfo *UserInfo) error {
 			return err
 		}
 	}
-	return nil
+
+	if len(roles) > 0 {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO user_info_roles
+			SELECT $1, roles.id FROM roles WHERE roles.name = ANY($2)`, userInfo.ID, pq.Array(roles))
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if int(rowsAffected) != len(roles) {
+			return ErrRecordNotFound
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (m UserInfoRepo) Get(id int64) (*UserInfo, error) {
@@ -74,7 +108,7 @@ func (m UserInfoRepo) Get(id int64) (*UserInfo, error) {
 	}
 
 	query := `
-			SELECT *
+			SELECT id, created_at, updated_at, fname, sname, email, password_hash, user_role, activated, version
 			FROM user_info
 			WHERE id = $1`
 
@@ -106,7 +140,7 @@ func (m UserInfoRepo) Get(id int64) (*UserInfo, error) {
 func (m UserInfoRepo) GetByEmail(email string) (*UserInfo, error) {
 	query := `
 			SELECT id, created_at, updated_at, fname, sname, email, password_hash, user_role, activated, version
-			FROM public.user_info
+			FROM user_info
 			WHERE email = $1
 `
 	var userInfo UserInfo
@@ -135,41 +169,69 @@ func (m UserInfoRepo) GetByEmail(email string) (*UserInfo, error) {
 	return &userInfo, nil
 }
 
-func (m UserInfoRepo) GetAll() ([]*UserInfo, error) {
-	query := `SELECT id, created_at, updated_at, fname, sname, email, password_hash, user_role, activated, version FROM user_info`
+// UserInfoSortSafelist holds the values accepted by the sort parameter when listing
+// user infos.
+var UserInfoSortSafelist = []string{"id", "created_at", "email", "name", "surname", "-id", "-created_at", "-email", "-name", "-surname"}
 
-	rows, err := m.DB.Query(query)
+// userInfoSortColumns maps the public sort keys onto user_info columns where the two
+// names differ.
+var userInfoSortColumns = map[string]string{
+	"name":    "fname",
+	"surname": "sname",
+}
+
+// GetAll returns a page of user infos. The search string is matched as a
+// case-insensitive substring of the name, surname and email, while an empty role or a
+// nil activated value means the corresponding filter isn't applied. The password hash
+// is never read.
+func (m UserInfoRepo) GetAll(search string, role string, activated *bool, filters Filters) ([]*UserInfo, Metadata, error) {
+	query := fmt.Sprintf(`
+			SELECT count(*) OVER(), id, created_at, updated_at, fname, sname, email, user_role, activated, version
+			FROM user_info
+			WHERE ($1 = '' OR fname ILIKE '%%' || $1 || '%%' OR sname ILIKE '%%' || $1 || '%%' OR email ILIKE '%%' || $1 || '%%')
+			AND ($2 = '' OR user_role = $2)
+			AND ($3::boolean IS NULL OR activated = $3)
+			ORDER BY %s %s, id ASC
+			LIMIT $4 OFFSET $5`, filters.sortColumn(userInfoSortColumns), filters.sortDirection())
+
+	args := []interface{}{search, role, activated, filters.limit(), filters.offset()}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
 	defer rows.Close()
 
+	totalRecords := 0
 	userInfos := []*UserInfo{}
 	for rows.Next() {
 		userInfo := &UserInfo{}
 		err = rows.Scan(
+			&totalRecords,
 			&userInfo.ID,
 			&userInfo.CreatedAt,
 			&userInfo.UpdatedAt,
 			&userInfo.Name,
 			&userInfo.Surname,
 			&userInfo.Email,
-			&userInfo.PasswordHashed.hash,
 			&userInfo.Role,
 			&userInfo.Activated,
 			&userInfo.Version,
 		)
 		if err != nil {
-			return nil, err
+			return nil, Metadata{}, err
 		}
 		userInfos = append(userInfos, userInfo)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
 
-	return userInfos, nil
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	return userInfos, metadata, nil
 }
 
 func (m UserInfoRepo) Update(userInfo *UserInfo) error {
@@ -179,8 +241,10 @@ func (m UserInfoRepo) Update(userInfo *UserInfo) error {
 			    fname = $1, 
 			    sname = $2, 
 			    email = $3, 
+			    password_hash = $4,
+			    activated = $5,
 			    version = version + 1
-			WHERE id = $4 AND version = $5
+			WHERE id = $6 AND version = $7
 			RETURNING version
 `
 
@@ -188,6 +252,8 @@ func (m UserInfoRepo) Update(userInfo *UserInfo) error {
 		userInfo.Name,
 		userInfo.Surname,
 		userInfo.Email,
+		userInfo.PasswordHashed.hash,
+		userInfo.Activated,
 		userInfo.ID,
 		userInfo.Version,
 	}
@@ -197,7 +263,7 @@ func (m UserInfoRepo) Update(userInfo *UserInfo) error {
 	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&userInfo.Version)
 	if err != nil {
 		switch {
-		case err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"`:
+		case err.Error() == `pq: duplicate key value violates unique constraint "user_info_email_key"`:
 			return ErrDuplicateEmail
 		case errors.Is(err, sql.ErrNoRows):
 			return ErrEditConflict
@@ -239,19 +305,19 @@ func (m UserInfoRepo) GetForToken(tokenScope, tokenPlaintext string) (*UserInfo,
 	// Remember that this returns a byte *array* with length 32, not a slice.
 	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
 	query := `
-			SELECT public.user_info.id, 
-			       public.user_info.created_at, 
-			       public.user_info.updated_at, 
-			       public.user_info.fname, 
-			       public.user_info.sname, 
-			       public.user_info.email, 
-			       public.user_info.password_hash, 
-			       public.user_info.activated, 
-			       public.user_info.user_role, 
-			       public.user_info.version
-			FROM public.user_info
+			SELECT user_info.id, 
+			       user_info.created_at, 
+			       user_info.updated_at, 
+			       user_info.fname, 
+			       user_info.sname, 
+			       user_info.email, 
+			       user_info.password_hash, 
+			       user_info.activated, 
+			       user_info.user_role, 
+			       user_info.version
+			FROM user_info
 			INNER JOIN user_info_tokens
-			ON public.user_info.id = user_info_tokens.user_info_id
+			ON user_info.id = user_info_tokens.user_info_id
 			WHERE user_info_tokens.hash = $1
 			AND user_info_tokens.scope = $2
 			AND user_info_tokens.expiry > $3
@@ -288,9 +354,18 @@ func (m UserInfoRepo) GetForToken(tokenScope, tokenPlaintext string) (*UserInfo,
 	return &userInfo, nil
 }
 
+func ValidateUserInfoName(v *validator.Validator, name string) {
+	v.Check(name != "", "name", "must be provided")
+	v.Check(len(name) <= 500, "name", "must not be more than 500 bytes long")
+}
+
+func ValidateUserInfoSurname(v *validator.Validator, surname string) {
+	v.Check(len(surname) <= 500, "surname", "must not be more than 500 bytes long")
+}
+
 func ValidateUserInfo(v *validator.Validator, userInfo *UserInfo) {
-	v.Check(userInfo.Name != "", "name", "must be provided")
-	v.Check(len(userInfo.Name) <= 500, "name", "must not be more than 500 bytes long")
+	ValidateUserInfoName(v, userInfo.Name)
+	ValidateUserInfoSurname(v, userInfo.Surname)
 	// Call the standalone ValidateEmail() helper.
 	ValidateEmail(v, userInfo.Email)
 	// If the plaintext password is not nil, call the standalone
@@ -310,9 +385,9 @@ func ValidateUserInfo(v *validator.Validator, userInfo *UserInfo) {
 
 func (m UserInfoRepo) FindNotActivatedAndExpired() ([]*UserInfo, error) {
 	query := `
-			SELECT u.*
+			SELECT u.id, u.created_at, u.updated_at, u.fname, u.sname, u.email, u.password_hash, u.user_role, u.activated, u.version
 			FROM user_info u
-			INNER JOIN public.user_info_tokens uit on u.id = uit.user_info_id
+			INNER JOIN user_info_tokens uit on u.id = uit.user_info_id
 			WHERE u.activated = false AND uit.expiry < now()
 `
 
@@ -6,7 +6,6 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/bxiit/greenlight/internal/validator"
-	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
@@ -213,11 +212,11 @@ type password struct {
 	hash      []byte
 }
 
-// The Set() method calculates the bcrypt hash of a plaintext password, and stores both
-// the hash and the plaintext versions in the struct.
+// The Set() method hashes a plaintext password with the configured PasswordHasher
+// (Argon2id unless SetPasswordHasher() was called), and stores both the hash and the
+// plaintext versions in the struct.
 func (p *password) Set(plaintextPassword string) error {
-	// $2b$[cost]$[22-character salt][31-character hash]
-	hash, err := bcrypt.GenerateFromPassword([]byte(plaintextPassword), 12)
+	hash, err := passwordHasher.Hash(plaintextPassword)
 	if err != nil {
 		return err
 	}
@@ -228,16 +227,20 @@ func (p *password) Set(plaintextPassword string) error {
 
 // The Matches() method checks whether the provided plaintext password matches the
 // hashed password stored in the struct, returning true if it matches and false
-// otherwise.
-func (p *password) Matches(plaintextPassword string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword(p.hash, []byte(plaintextPassword))
-	if err != nil {
-		switch {
-		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
-			return false, nil
-		default:
-			return false, err
+// otherwise. When it matches, outdated reports whether the hash was made by another
+// hasher or with other parameters than the configured one, meaning the password
+// should be Set() again and saved.
+func (p *password) Matches(plaintextPassword string) (match bool, outdated bool, err error) {
+	for _, hasher := range append([]PasswordHasher{passwordHasher}, knownHashers...) {
+		if !hasher.Recognises(p.hash) {
+			continue
+		}
+		match, err = hasher.Compare(p.hash, plaintextPassword)
+		if err != nil || !match {
+			return false, false, err
 		}
+		outdated = !passwordHasher.Recognises(p.hash) || !passwordHasher.Current(p.hash)
+		return true, outdated, nil
 	}
-	return true, nil
+	return false, false, ErrUnknownHash
 }
@@ -0,0 +1,221 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidToken is returned by Parse() for any token which is malformed, expired, or
+// not signed by one of the keys in the keyset.
+var ErrInvalidToken = errors.New("invalid token")
+
+const issuer = "greenlight"
+
+// Claims is the payload of an access token. It carries enough about the user info for
+// the authenticate and requirePermission middleware to work without touching the
+// database.
+type Claims struct {
+	Role        string   `json:"role"`
+	Activated   bool     `json:"activated"`
+	Permissions []string `json:"permissions"`
+	// SessionID is the hex encoded family of the refresh token issued alongside the
+	// access token, so that logging out can revoke it.
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the user info ID stored in the subject claim. A subject which isn't
+// a positive integer gives an error.
+func (c *Claims) UserID() (int64, error) {
+	id, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id < 1 {
+		return 0, fmt.Errorf("subject %q is not a user info ID", c.Subject)
+	}
+	return id, nil
+}
+
+type key struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	canSign   bool
+}
+
+// Keyset holds the keys read from the keyset file. Tokens are signed with the active
+// key and its ID is written to the "kid" header; every key in the set is accepted when
+// verifying. To rotate, add a new key, make it active and keep the old one in the file
+// until the tokens it signed have expired.
+type Keyset struct {
+	active *key
+	keys   map[string]*key
+}
+
+// The keyset file is a JSON document like:
+//
+//	{
+//	  "active": "2024-05",
+//	  "keys": [
+//	    {"kid": "2024-05", "alg": "EdDSA", "private_key": "<base64 seed>"},
+//	    {"kid": "2024-01", "alg": "HS256", "secret": "<base64 secret>"}
+//	  ]
+//	}
+//
+// Retired Ed25519 keys may be listed with only a public_key, which allows verifying
+// but not signing with them.
+type keysetFile struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		Kid        string `json:"kid"`
+		Alg        string `json:"alg"`
+		Secret     string `json:"secret"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+	} `json:"keys"`
+}
+
+// Load reads the keyset file at path.
+func Load(path string) (*Keyset, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keysetFile
+	err = json.Unmarshal(b, &file)
+	if err != nil {
+		return nil, fmt.Errorf("keyset %s: %w", path, err)
+	}
+
+	ks := &Keyset{keys: make(map[string]*key)}
+	for _, k := range file.Keys {
+		if k.Kid == "" {
+			return nil, fmt.Errorf("keyset %s: key without kid", path)
+		}
+		if _, exists := ks.keys[k.Kid]; exists {
+			return nil, fmt.Errorf("keyset %s: duplicate kid %q", path, k.Kid)
+		}
+
+		parsed := &key{id: k.Kid}
+		switch k.Alg {
+		case "HS256":
+			secret, err := base64.StdEncoding.DecodeString(k.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("keyset %s: key %q: %w", path, k.Kid, err)
+			}
+			if len(secret) < 32 {
+				return nil, fmt.Errorf("keyset %s: key %q: secret must be at least 32 bytes", path, k.Kid)
+			}
+			parsed.method = jwt.SigningMethodHS256
+			parsed.signKey = secret
+			parsed.verifyKey = secret
+			parsed.canSign = true
+		case "EdDSA":
+			parsed.method = jwt.SigningMethodEdDSA
+			switch {
+			case k.PrivateKey != "":
+				seed, err := base64.StdEncoding.DecodeString(k.PrivateKey)
+				if err != nil {
+					return nil, fmt.Errorf("keyset %s: key %q: %w", path, k.Kid, err)
+				}
+				if len(seed) != ed25519.SeedSize {
+					return nil, fmt.Errorf("keyset %s: key %q: private_key must be a %d byte seed", path, k.Kid, ed25519.SeedSize)
+				}
+				privateKey := ed25519.NewKeyFromSeed(seed)
+				parsed.signKey = privateKey
+				parsed.verifyKey = privateKey.Public()
+				parsed.canSign = true
+			case k.PublicKey != "":
+				publicKey, err := base64.StdEncoding.DecodeString(k.PublicKey)
+				if err != nil {
+					return nil, fmt.Errorf("keyset %s: key %q: %w", path, k.Kid, err)
+				}
+				if len(publicKey) != ed25519.PublicKeySize {
+					return nil, fmt.Errorf("keyset %s: key %q: public_key must be %d bytes", path, k.Kid, ed25519.PublicKeySize)
+				}
+				parsed.verifyKey = ed25519.PublicKey(publicKey)
+			default:
+				return nil, fmt.Errorf("keyset %s: key %q: private_key or public_key must be provided", path, k.Kid)
+			}
+		default:
+			return nil, fmt.Errorf("keyset %s: key %q: unsupported alg %q", path, k.Kid, k.Alg)
+		}
+		ks.keys[k.Kid] = parsed
+	}
+
+	active, ok := ks.keys[file.Active]
+	if !ok {
+		return nil, fmt.Errorf("keyset %s: active key %q not found", path, file.Active)
+	}
+	if !active.canSign {
+		return nil, fmt.Errorf("keyset %s: active key %q can't sign", path, file.Active)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// Sign fills in the registered claims for the user info and returns the signed token
+// together with its expiry.
+func (ks *Keyset) Sign(userID int64, ttl time.Duration, claims Claims) (string, time.Time, error) {
+	now := time.Now()
+	expiry := now.Add(ttl)
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   strconv.FormatInt(userID, 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiry),
+	}
+
+	token := jwt.NewWithClaims(ks.active.method, claims)
+	token.Header["kid"] = ks.active.id
+
+	signed, err := token.SignedString(ks.active.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiry, nil
+}
+
+// Parse verifies the token against the key named in its "kid" header and returns its
+// claims. Any failure is reported as ErrInvalidToken.
+func (ks *Keyset) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		k, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		// Don't let a token choose how it is verified: the algorithm has to be the
+		// one configured for its key.
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("unexpected alg %q for kid %q", token.Method.Alg(), kid)
+		}
+		return k.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if _, err := claims.UserID(); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,123 @@
+package jwtauth
+
+import (
+	"encoding/base64"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("s", 32)))
+	testSeed   = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", 32)))
+)
+
+func writeKeyset(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keyset.json")
+	err := os.WriteFile(path, []byte(contents), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSignAndParse(t *testing.T) {
+	for _, active := range []string{"hmac", "ed"} {
+		t.Run(active, func(t *testing.T) {
+			ks, err := Load(writeKeyset(t, `{"active": "`+active+`", "keys": [
+				{"kid": "hmac", "alg": "HS256", "secret": "`+testSecret+`"},
+				{"kid": "ed", "alg": "EdDSA", "private_key": "`+testSeed+`"}]}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signed, _, err := ks.Sign(42, time.Minute, Claims{Role: "admin", Permissions: []string{"module_info:write"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := ks.Parse(signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			userID, _ := claims.UserID()
+			if userID != 42 || claims.Role != "admin" || len(claims.Permissions) != 1 {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestParseAfterRotation(t *testing.T) {
+	old, err := Load(writeKeyset(t, `{"active": "old", "keys": [
+		{"kid": "old", "alg": "HS256", "secret": "`+testSecret+`"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, _, err := old.Sign(1, time.Minute, Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := Load(writeKeyset(t, `{"active": "new", "keys": [
+		{"kid": "new", "alg": "EdDSA", "private_key": "`+testSeed+`"},
+		{"kid": "old", "alg": "HS256", "secret": "`+testSecret+`"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rotated.Parse(signed); err != nil {
+		t.Fatalf("token signed with a retired key was rejected: %v", err)
+	}
+
+	retired, err := Load(writeKeyset(t, `{"active": "new", "keys": [
+		{"kid": "new", "alg": "EdDSA", "private_key": "`+testSeed+`"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retired.Parse(signed); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for a removed key, got %v", err)
+	}
+}
+
+func TestParseRejectsExpiredToken(t *testing.T) {
+	ks, err := Load(writeKeyset(t, `{"active": "hmac", "keys": [
+		{"kid": "hmac", "alg": "HS256", "secret": "`+testSecret+`"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, _, err := ks.Sign(1, -time.Minute, Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Parse(signed); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestParseRejectsInvalidSubject(t *testing.T) {
+	ks, err := Load(writeKeyset(t, `{"active": "hmac", "keys": [
+		{"kid": "hmac", "alg": "HS256", "secret": "`+testSecret+`"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, subject := range []string{"", "alice", "0", "-1"} {
+		token := jwt.NewWithClaims(ks.active.method, Claims{RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}})
+		token.Header["kid"] = ks.active.id
+		signed, err := token.SignedString(ks.active.signKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ks.Parse(signed); err != ErrInvalidToken {
+			t.Fatalf("subject %q: expected ErrInvalidToken, got %v", subject, err)
+		}
+	}
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS module_info_module_name_idx;
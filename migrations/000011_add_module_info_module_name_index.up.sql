@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS module_info_module_name_idx ON module_info USING GIN (to_tsvector('simple', module_name));
@@ -0,0 +1,2 @@
+ALTER TABLE department_info
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS user_info_email_changes
+(
+    hash      bytea PRIMARY KEY REFERENCES user_info_tokens (hash) ON DELETE CASCADE,
+    new_email citext NOT NULL
+);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS user_info_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+
+DELETE FROM permissions
+WHERE code IN ('user_info:read', 'user_info:write', 'module_info:read', 'module_info:write',
+               'department_info:read', 'department_info:write', 'roles:read', 'roles:write');
+
+ALTER TABLE permissions
+    DROP CONSTRAINT IF EXISTS permissions_code_key;
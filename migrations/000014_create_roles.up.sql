@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+    id   bigserial PRIMARY KEY,
+    name text UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       bigint NOT NULL REFERENCES roles ON DELETE CASCADE,
+    permission_id bigint NOT NULL REFERENCES permissions ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_info_roles
+(
+    user_info_id bigint NOT NULL REFERENCES user_info ON DELETE CASCADE,
+    role_id      bigint NOT NULL REFERENCES roles ON DELETE CASCADE,
+    PRIMARY KEY (user_info_id, role_id)
+);
+
+ALTER TABLE permissions
+    ADD CONSTRAINT permissions_code_key UNIQUE (code);
+
+INSERT INTO permissions (code)
+VALUES ('user_info:read'),
+       ('user_info:write'),
+       ('module_info:read'),
+       ('module_info:write'),
+       ('department_info:read'),
+       ('department_info:write'),
+       ('roles:read'),
+       ('roles:write')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO roles (name)
+VALUES ('user'),
+       ('admin')
+ON CONFLICT (name) DO NOTHING;
+
+-- Regular users can browse modules and departments, admins can do everything.
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles
+         INNER JOIN permissions ON permissions.code IN ('module_info:read', 'department_info:read')
+WHERE roles.name = 'user'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles
+         CROSS JOIN permissions
+WHERE roles.name = 'admin'
+  AND permissions.code NOT LIKE 'movies:%'
+ON CONFLICT DO NOTHING;
+
+-- Carry the existing user_role values over to the new tables.
+INSERT INTO user_info_roles (user_info_id, role_id)
+SELECT user_info.id, roles.id
+FROM user_info
+         INNER JOIN roles ON roles.name = COALESCE(user_info.user_role, 'user')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS permission_audit;
+
+DELETE FROM permissions
+WHERE code IN ('permissions:read', 'permissions:write');
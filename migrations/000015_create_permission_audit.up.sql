@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS permission_audit
+(
+    id           bigserial PRIMARY KEY,
+    created_at   timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    actor_id     bigint REFERENCES user_info ON DELETE SET NULL,
+    action       text                        NOT NULL,
+    code         text                        NOT NULL,
+    user_info_id bigint REFERENCES user_info ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS permission_audit_user_info_id_idx ON permission_audit (user_info_id);
+
+INSERT INTO permissions (code)
+VALUES ('permissions:read'),
+       ('permissions:write')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles
+         INNER JOIN permissions ON permissions.code IN ('permissions:read', 'permissions:write')
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS user_info_tokens_family_idx;
+
+ALTER TABLE user_info_tokens
+    DROP COLUMN IF EXISTS used,
+    DROP COLUMN IF EXISTS family;
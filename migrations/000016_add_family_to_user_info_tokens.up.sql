@@ -0,0 +1,7 @@
+-- Tokens issued by the same login share a family so that the whole chain can be
+-- revoked at once. used marks refresh tokens which have already been exchanged.
+ALTER TABLE user_info_tokens
+    ADD COLUMN IF NOT EXISTS family bytea,
+    ADD COLUMN IF NOT EXISTS used   boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS user_info_tokens_family_idx ON user_info_tokens (family);
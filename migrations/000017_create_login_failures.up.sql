@@ -0,0 +1,8 @@
+-- Failed logins are counted per key, where a key is "email:<address>" or "ip:<addr>".
+CREATE TABLE IF NOT EXISTS login_failures
+(
+    key          text PRIMARY KEY,
+    failures     integer                     NOT NULL DEFAULT 0,
+    last_failure timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    locked_until timestamp(0) with time zone
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_info_recovery_codes;
+DROP TABLE IF EXISTS user_info_mfa;
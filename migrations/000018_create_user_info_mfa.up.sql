@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS user_info_mfa
+(
+    user_info_id   bigint PRIMARY KEY REFERENCES user_info ON DELETE CASCADE,
+    secret         text                        NOT NULL,
+    enabled        boolean                     NOT NULL DEFAULT false,
+    last_used_step bigint                      NOT NULL DEFAULT 0,
+    created_at     timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_info_recovery_codes
+(
+    user_info_id bigint NOT NULL REFERENCES user_info ON DELETE CASCADE,
+    hash         bytea  NOT NULL,
+    PRIMARY KEY (user_info_id, hash)
+);